@@ -3,8 +3,10 @@ package auction
 import (
 	"github.com/nspcc-dev/neo-go/pkg/interop"
 	"github.com/nspcc-dev/neo-go/pkg/interop/contract"
+	"github.com/nspcc-dev/neo-go/pkg/interop/iterator"
 	"github.com/nspcc-dev/neo-go/pkg/interop/lib/address"
 	"github.com/nspcc-dev/neo-go/pkg/interop/native/management"
+	"github.com/nspcc-dev/neo-go/pkg/interop/native/std"
 	"github.com/nspcc-dev/neo-go/pkg/interop/runtime"
 	"github.com/nspcc-dev/neo-go/pkg/interop/storage"
 )
@@ -16,13 +18,45 @@ const (
 	lotKey             = "l" // nft id
 	organizerKey       = "o" // organizer of the auction
 	potentialWinnerKey = "w" // owner of the last bet
+	auctionIDKey       = "n" // id of the last started auction
+	auctionDomainKey   = "d" // nns subdomain of the current auction
+
+	auctionPrefix = "a" // auction id -> AuctionState, история всех аукционов
+	bidPrefix     = "b" // auction id + bid number -> Bid
+	userBidPrefix = "u" // user + auction id + bid number -> Bid
 
 	nnsSelfDomain         = "auc.auc"
 	nnsNftDomain          = "nft.auc"
-	nnsRecordType         = 16
+	nnsRecordType         = 80 // HASH160 record, it holds the address of the contract
 	nnsContractHashString = "NcCZaxnLkXvrd56DgpFSSBjhj2DqzH3jKP"
+	nnsTXTRecordType      = 16
+
+	maxBidsLimit = 100 // больше ставок за вызов не отдаем, иначе массив не поместится на стек
 )
 
+// AuctionState is a record of started auction, it's kept after the auction is finished.
+type AuctionState struct {
+	ID         int
+	Organizer  interop.Hash160
+	LotID      []byte
+	InitialBet int
+	CurrentBet int
+	Winner     interop.Hash160 // nil, пока нет ставок
+	Domain     string
+	Finished   bool
+	BidCount   int
+	StartedAt  int // время блока в миллисекундах
+	FinishedAt int
+}
+
+// Bid is a bet made in the auction.
+type Bid struct {
+	AuctionID int
+	Bidder    interop.Hash160
+	Bet       int
+	Time      int
+}
+
 type AuctionItem struct {
 	Owner      interop.Hash160
 	InitialBet int
@@ -31,26 +65,43 @@ type AuctionItem struct {
 }
 
 func _deploy(data interface{}, isUpdate bool) {
+	selfHash := runtime.GetExecutingScriptHash()
 	if isUpdate {
+		// контракты, задеплоенные до поддоменов аукционов, админами своего домена еще не были
+		setSelfDomainAdmin(selfHash)
 		return
 	}
 
 	// регистрация в nns (при update хэш контракта не меняется, поэтому и в nns ничего не надо обновлять)
-	selfHash := runtime.GetExecutingScriptHash()
 	contract.Call(address.ToHash160(nnsContractHashString), "register", contract.All, nnsSelfDomain, address.ToHash160("NfgHwwTi3wHAS8aFAN243C5vGbkYDpqLHP"), "owner_email@mail.ru", 100, 100, 31536000, 31536000)
 	currentNnsRecord := contract.Call(address.ToHash160(nnsContractHashString), "getRecords", contract.All, nnsSelfDomain, nnsRecordType)
 	if currentNnsRecord != nil {
 		contract.Call(address.ToHash160(nnsContractHashString), "deleteRecords", contract.All, nnsSelfDomain, nnsRecordType)
 	}
 	contract.Call(address.ToHash160(nnsContractHashString), "addRecord", contract.All, nnsSelfDomain, nnsRecordType, address.FromHash160(selfHash))
+	setSelfDomainAdmin(selfHash)
+}
+
+// setSelfDomainAdmin makes the contract admin of its domain, so that it can register
+// auction subdomains itself. The domain owner must witness the deploy or update.
+func setSelfDomainAdmin(selfHash interop.Hash160) {
+	contract.Call(address.ToHash160(nnsContractHashString), "setAdmin", contract.All, nnsSelfDomain, selfHash)
+}
 
+// RenewDomain renews the contract domain in nns for a year and returns its new
+// expiration. The contract is the domain admin, so only it can renew the domain,
+// the renewal price is burnt from the fee of the calling transaction.
+func RenewDomain() int {
+	return contract.Call(address.ToHash160(nnsContractHashString), "renew", contract.All, nnsSelfDomain).(int)
 }
 
 func Update(script []byte, manifest []byte, data any) {
 	management.UpdateWithData(script, manifest, data)
 }
 
-func Start(auctionOwner interop.Hash160, lotId []byte, initBet int) {
+// Start starts a new auction, if name is not empty, the auction becomes addressable
+// as name.auc.auc in nns until it's finished.
+func Start(auctionOwner interop.Hash160, lotId []byte, initBet int, name string) {
 	ctx := storage.GetContext()
 
 	currentOwner := storage.Get(ctx, organizerKey)
@@ -61,9 +112,8 @@ func Start(auctionOwner interop.Hash160, lotId []byte, initBet int) {
 		panic("initial bet must not be negative")
 	}
 
-	nftContractHashStringArray := contract.Call(address.ToHash160(nnsContractHashString), "resolve", contract.All, nnsNftDomain, nnsRecordType).([]string)
-	nftContractHashString := nftContractHashStringArray[0]
-	ownerOfLot := contract.Call(address.ToHash160(nftContractHashString), "ownerOf", contract.All, lotId).(interop.Hash160)
+	nftContractHash := contract.Call(address.ToHash160(nnsContractHashString), "resolveHash", contract.All, nnsNftDomain).(interop.Hash160)
+	ownerOfLot := contract.Call(nftContractHash, "ownerOf", contract.All, lotId).(interop.Hash160)
 	if !ownerOfLot.Equals(auctionOwner) {
 		panic("you can't start auction with this lot because you're not its owner")
 	}
@@ -73,7 +123,28 @@ func Start(auctionOwner interop.Hash160, lotId []byte, initBet int) {
 	storage.Put(ctx, initBetKey, initBet)
 	storage.Put(ctx, currentBetKey, initBet)
 
+	auctionID := lastAuctionID(ctx) + 1
+	storage.Put(ctx, auctionIDKey, auctionID)
+
+	domain := ""
+	if name != "" {
+		domain = name + "." + nnsSelfDomain
+		registerAuctionDomain(domain, auctionID, lotId)
+		storage.Put(ctx, auctionDomainKey, domain)
+	}
+
+	putAuctionState(ctx, AuctionState{
+		ID:         auctionID,
+		Organizer:  auctionOwner,
+		LotID:      lotId,
+		InitialBet: initBet,
+		CurrentBet: initBet,
+		Domain:     domain,
+		StartedAt:  runtime.GetTime(),
+	})
+
 	runtime.Notify("info", []byte("New auction started with initial bet = "+intToStr(initBet)+" by user "+address.FromHash160(auctionOwner)))
+	runtime.Notify("AuctionStarted", auctionID, auctionOwner, lotId, initBet, domain)
 }
 
 func MakeBet(better interop.Hash160, bet int) {
@@ -95,12 +166,26 @@ func MakeBet(better interop.Hash160, bet int) {
 	storage.Put(ctx, currentBetKey, bet)
 	storage.Put(ctx, potentialWinnerKey, better)
 
+	if st := getAuctionState(ctx, lastAuctionID(ctx)); st != nil { // аукционы, начатые до обновления контракта, в истории не сохранены
+		auc := st.(AuctionState)
+		b := Bid{AuctionID: auc.ID, Bidder: better, Bet: bet, Time: runtime.GetTime()}
+		bid := std.Serialize(b)
+		storage.Put(ctx, mkBidKey(auc.ID, auc.BidCount), bid)
+		storage.Put(ctx, mkUserBidKey(better, auc.ID, auc.BidCount), bid)
+
+		auc.CurrentBet = bet
+		auc.Winner = better
+		auc.BidCount++
+		putAuctionState(ctx, auc)
+	}
+
 	runtime.Notify("info", []byte("New bet = "+intToStr(bet)+" is made by user "+address.FromHash160(better)))
+	runtime.Notify("BidMade", lastAuctionID(ctx), better, bet)
 
 }
 
 func Finish(finishInitiator interop.Hash160) interop.Hash160 {
-	ctx := storage.GetReadOnlyContext()
+	ctx := storage.GetContext()
 
 	lotData := storage.Get(ctx, lotKey)
 	if lotData == nil {
@@ -121,13 +206,29 @@ func Finish(finishInitiator interop.Hash160) interop.Hash160 {
 		winner = winnerData.(interop.Hash160)
 	}
 
-	nftContractHashStringArray := contract.Call(address.ToHash160(nnsContractHashString), "resolve", contract.All, nnsNftDomain, nnsRecordType).([]string)
-	nftContractHashString := nftContractHashStringArray[0]
-	contract.Call(address.ToHash160(nftContractHashString), "transfer", contract.All, winner, lotID, nil)
+	nftContractHash := contract.Call(address.ToHash160(nnsContractHashString), "resolveHash", contract.All, nnsNftDomain).(interop.Hash160)
+	contract.Call(nftContractHash, "transfer", contract.All, winner, lotID, nil)
+
+	domainData := storage.Get(ctx, auctionDomainKey)
+	if domainData != nil { // аукцион завершен, его поддомен больше не нужен
+		deleteAuctionDomain(domainData.(string))
+	}
+
+	if st := getAuctionState(ctx, lastAuctionID(ctx)); st != nil {
+		auc := st.(AuctionState)
+		auc.Winner = winner
+		auc.Finished = true
+		auc.FinishedAt = runtime.GetTime()
+		putAuctionState(ctx, auc)
+	}
+
+	finalBet := storage.Get(ctx, currentBetKey).(int)
+	auctionID := lastAuctionID(ctx)
 
 	clearStorage()
 
 	runtime.Notify("info", []byte("Auction has been finished. Winner is: "+address.FromHash160(winner)))
+	runtime.Notify("AuctionFinished", auctionID, winner, lotID, finalBet)
 
 	return winner
 }
@@ -163,6 +264,39 @@ func intToStr(value int) string {
 	return result
 }
 
+// registerAuctionDomain registers nns subdomain owned by the contract with TXT records
+// holding the auction id and the lot.
+func registerAuctionDomain(domain string, auctionID int, lotId []byte) {
+	nnsHash := address.ToHash160(nnsContractHashString)
+	selfHash := runtime.GetExecutingScriptHash()
+
+	contract.Call(nnsHash, "register", contract.All, domain, selfHash, "owner_email@mail.ru", 100, 100, 31536000, 31536000)
+	contract.Call(nnsHash, "addRecord", contract.All, domain, nnsTXTRecordType, "auction="+intToStr(auctionID))
+	contract.Call(nnsHash, "addRecord", contract.All, domain, nnsTXTRecordType, "lot="+bytesToHex(lotId))
+}
+
+// deleteAuctionDomain deletes nns subdomain of the finished auction. The domain
+// could have expired or been deleted already, it mustn't keep the auction from finishing.
+func deleteAuctionDomain(domain string) {
+	defer func() {
+		if r := recover(); r != nil {
+			runtime.Log("can't delete auction domain " + domain)
+		}
+	}()
+
+	contract.Call(address.ToHash160(nnsContractHashString), "deleteDomain", contract.All, domain)
+}
+
+func bytesToHex(data []byte) string {
+	var chars = "0123456789abcdef"
+	var result string
+	for i := 0; i < len(data); i++ {
+		result = result + string(chars[data[i]>>4]) + string(chars[data[i]&0x0f])
+	}
+
+	return result
+}
+
 // clearStorage in this moment this func delete all values storage by hardcode prefix
 func clearStorage() {
 	ctx := storage.GetContext()
@@ -172,4 +306,96 @@ func clearStorage() {
 	storage.Delete(ctx, potentialWinnerKey)
 	storage.Delete(ctx, lotKey)
 	storage.Delete(ctx, organizerKey)
+	storage.Delete(ctx, auctionDomainKey)
+}
+
+// LastAuctionID returns id of the last started auction, 0 if there were none.
+func LastAuctionID() int {
+	return lastAuctionID(storage.GetReadOnlyContext())
+}
+
+func lastAuctionID(ctx storage.Context) int {
+	data := storage.Get(ctx, auctionIDKey)
+	if data == nil {
+		return 0
+	}
+	return data.(int)
+}
+
+// GetAuction returns the auction with the given id, both current and finished.
+func GetAuction(id int) AuctionState {
+	st := getAuctionState(storage.GetReadOnlyContext(), id)
+	if st == nil {
+		panic("auction not found")
+	}
+	return st.(AuctionState)
+}
+
+// GetBids returns at most limit (up to maxBidsLimit) bids of the auction in the
+// order they were made, skipping the first offset ones.
+func GetBids(id int, offset int, limit int) []Bid {
+	return findBids(mkBidPrefix(id), offset, limit)
+}
+
+// GetUserBids returns bids made by the user in all auctions, paged like GetBids.
+func GetUserBids(user interop.Hash160, offset int, limit int) []Bid {
+	if len(user) != 20 {
+		panic("invalid user address")
+	}
+	return findBids(append([]byte(userBidPrefix), user...), offset, limit)
+}
+
+func findBids(prefix []byte, offset int, limit int) []Bid {
+	if offset < 0 || limit <= 0 {
+		panic("invalid offset or limit")
+	}
+	if limit > maxBidsLimit {
+		limit = maxBidsLimit
+	}
+
+	res := []Bid{}
+	iter := storage.Find(storage.GetReadOnlyContext(), prefix, storage.ValuesOnly|storage.DeserializeValues)
+	for len(res) < limit && iterator.Next(iter) {
+		if offset > 0 {
+			offset--
+			continue
+		}
+		res = append(res, iterator.Value(iter).(Bid))
+	}
+	return res
+}
+
+func getAuctionState(ctx storage.Context, id int) any {
+	data := storage.Get(ctx, mkAuctionKey(id))
+	if data == nil {
+		return nil
+	}
+	return std.Deserialize(data.([]byte)).(AuctionState)
+}
+
+func putAuctionState(ctx storage.Context, auc AuctionState) {
+	storage.Put(ctx, mkAuctionKey(auc.ID), std.Serialize(auc))
+}
+
+// mkAuctionKey creates DB key for the auction, ids are big-endian, so auctions are iterated in order.
+func mkAuctionKey(id int) []byte {
+	return append([]byte(auctionPrefix), idToBytes(id)...)
+}
+
+func mkBidPrefix(id int) []byte {
+	return append([]byte(bidPrefix), idToBytes(id)...)
+}
+
+func mkBidKey(id int, n int) []byte {
+	return append(mkBidPrefix(id), idToBytes(n)...)
+}
+
+func mkUserBidKey(user interop.Hash160, id int, n int) []byte {
+	res := append([]byte(userBidPrefix), user...)
+	res = append(res, idToBytes(id)...)
+	return append(res, idToBytes(n)...)
+}
+
+func idToBytes(id int) []byte {
+	return []byte{byte(id >> 24), byte(id >> 16), byte(id >> 8), byte(id)}
 }
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/unwrap"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultRateLimit = 5 // запросов в минуту
+
+	nonceTTL  = time.Minute
+	nonceSize = 32
+
+	maxRequestBody  = 64 << 10
+	shutdownTimeout = 10 * time.Second
+
+	// Заголовки подписанного запроса, подписывается authMessage.
+	headerPublicKey = "X-Neo-Public-Key"
+	headerNonce     = "X-Neo-Nonce"
+	headerSignature = "X-Neo-Signature"
+
+	authMessagePrefix = "auction api:"
+)
+
+type nonceResponse struct {
+	Nonce     string    `json:"nonce"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// authenticator issues nonces and verifies signed requests. Nonces are bound
+// to the address they are issued for and can be used only once.
+type authenticator struct {
+	admins map[util.Uint160]struct{} // кому доступны служебные методы
+
+	mu     sync.Mutex
+	nonces map[string]nonce // nonce в hex -> nonce, у адреса их может быть несколько, чтобы чужой запрос не вытеснял его nonce
+}
+
+type nonce struct {
+	data      []byte
+	user      util.Uint160
+	expiresAt time.Time
+}
+
+// rateLimiter limits number of requests per minute for each id (address, IP).
+type rateLimiter struct {
+	limit int
+
+	mu       sync.Mutex
+	requests map[string][]time.Time
+}
+
+type authUserKey struct{}
+
+func newAuthenticator(admins map[util.Uint160]struct{}) *authenticator {
+	return &authenticator{
+		admins: admins,
+		nonces: make(map[string]nonce),
+	}
+}
+
+func newRateLimiter(limit int) *rateLimiter {
+	if limit <= 0 {
+		limit = defaultRateLimit
+	}
+	return &rateLimiter{limit: limit, requests: make(map[string][]time.Time)}
+}
+
+// allow checks rate limits of all the ids and accounts the request if it fits.
+func (l *rateLimiter) allow(now time.Time, ids ...string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, id := range ids {
+		reqs := l.requests[id]
+		for len(reqs) != 0 && now.Sub(reqs[0]) >= time.Minute {
+			reqs = reqs[1:]
+		}
+		l.requests[id] = reqs
+		if len(reqs) >= l.limit {
+			return false
+		}
+	}
+
+	for _, id := range ids {
+		l.requests[id] = append(l.requests[id], now)
+	}
+
+	for id, reqs := range l.requests { // чтобы карта не росла бесконечно
+		if len(reqs) == 0 || now.Sub(reqs[len(reqs)-1]) >= time.Minute {
+			delete(l.requests, id)
+		}
+	}
+
+	return true
+}
+
+func (a *authenticator) newNonce(sh util.Uint160, now time.Time) (nonce, error) {
+	n := nonce{data: make([]byte, nonceSize), user: sh, expiresAt: now.Add(nonceTTL)}
+	if _, err := rand.Read(n.data); err != nil {
+		return nonce{}, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for k, old := range a.nonces {
+		if now.After(old.expiresAt) {
+			delete(a.nonces, k)
+		}
+	}
+	a.nonces[hex.EncodeToString(n.data)] = n
+
+	return n, nil
+}
+
+func (a *authenticator) useNonce(sh util.Uint160, data []byte, now time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := hex.EncodeToString(data)
+	n, ok := a.nonces[key]
+	if !ok || now.After(n.expiresAt) || !n.user.Equals(sh) {
+		return false
+	}
+	delete(a.nonces, key)
+	return true
+}
+
+// authMessage is the message signed by the client, it binds the nonce to the
+// exact request, so the signature can't be reused for another one.
+func authMessage(nonce, method, path string, body []byte) []byte {
+	bodyHash := sha256.Sum256(body)
+	return []byte(authMessagePrefix + nonce + ":" + method + ":" + path + ":" + hex.EncodeToString(bodyHash[:]))
+}
+
+// verify checks the request signature and returns the address of the signer,
+// body of the request is read and replaced with a copy.
+func (a *authenticator) verify(r *http.Request, now time.Time) (util.Uint160, error) {
+	pub, err := keys.NewPublicKeyFromString(r.Header.Get(headerPublicKey))
+	if err != nil {
+		return util.Uint160{}, fmt.Errorf("invalid public key: %w", err)
+	}
+	nonceHex := r.Header.Get(headerNonce)
+	nonceData, err := hex.DecodeString(nonceHex)
+	if err != nil {
+		return util.Uint160{}, fmt.Errorf("invalid nonce: %w", err)
+	}
+	sig, err := hex.DecodeString(r.Header.Get(headerSignature))
+	if err != nil {
+		return util.Uint160{}, fmt.Errorf("invalid signature: %w", err)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBody))
+	if err != nil {
+		return util.Uint160{}, fmt.Errorf("read body: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	msg := authMessage(nonceHex, r.Method, r.URL.Path, body)
+	if !pub.Verify(sig, hash.Sha256(msg).BytesBE()) {
+		return util.Uint160{}, errors.New("wrong signature")
+	}
+
+	sh := pub.GetScriptHash()
+	if !a.useNonce(sh, nonceData, now) {
+		return util.Uint160{}, errors.New("unknown or expired nonce")
+	}
+
+	return sh, nil
+}
+
+// authUser returns the address which signed the request.
+func authUser(r *http.Request) util.Uint160 {
+	return r.Context().Value(authUserKey{}).(util.Uint160)
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// limited rejects requests exceeding the rate limit per IP.
+func (s *Server) limited(prefix string, next http.HandlerFunc) http.HandlerFunc {
+	return s.limitedBy(s.limiter, prefix, next)
+}
+
+// limitedBy is limited with the given rate limiter.
+func (s *Server) limitedBy(l *rateLimiter, prefix string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !l.allow(time.Now(), prefix+":ip:"+remoteIP(r)) {
+			s.log.Warn("rate limited", zap.String("path", r.URL.Path), zap.String("ip", remoteIP(r)))
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// authenticated passes only signed requests, the signer is available via authUser.
+func (s *Server) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := s.auth.verify(r, time.Now())
+		if err != nil {
+			s.log.Warn("unauthorized request", zap.String("path", r.URL.Path), zap.String("ip", remoteIP(r)), zap.Error(err))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		next(w, r.WithContext(context.WithValue(r.Context(), authUserKey{}, user)))
+	}
+}
+
+// adminOnly passes only requests signed by one of api_admins.
+func (s *Server) adminOnly(next http.HandlerFunc) http.HandlerFunc {
+	return s.authenticated(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := s.auth.admins[authUser(r)]; !ok {
+			s.log.Warn("forbidden request", zap.String("path", r.URL.Path), zap.String("user", address.Uint160ToString(authUser(r))))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	})
+}
+
+// newAPI builds the backend HTTP API. Public methods only read the chain state,
+// privileged ones require a request signed with the wallet key.
+func (s *Server) newAPI() *http.ServeMux {
+	mux := http.NewServeMux()
+
+	// публичные методы
+	mux.HandleFunc("GET /status", s.statusHandler)                                      // текущие хэши контрактов и время их последнего обновления
+	mux.HandleFunc("GET /properties/{tokenID}", s.propertiesHandler)                    // свойства nft токена
+	mux.HandleFunc("GET /auth/nonce/{userAddress}", s.limited("nonce", s.nonceHandler)) // nonce для подписи запроса
+
+	// мониторинг
+	mux.Handle("GET /metrics", promhttp.Handler())
+	mux.HandleFunc("GET /healthz", s.healthzHandler) // процесс жив
+	mux.HandleFunc("GET /readyz", s.readyzHandler)   // rpc, подписка и frostfs доступны
+
+	// состояние и история аукционов, ответы кэшируются до следующего блока
+	state := func(next http.HandlerFunc) http.HandlerFunc { return s.limitedBy(s.readRate, "state", next) }
+	mux.HandleFunc("GET /auctions", state(s.pagedJSON(defaultAuctionsLimit, maxAuctionsLimit, s.auctionsHandler)))
+	mux.HandleFunc("GET /auctions/{id}", state(s.cachedJSON(s.auctionHandler)))
+	mux.HandleFunc("GET /auctions/{id}/bids", state(s.pagedJSON(defaultBidsLimit, maxBidsLimit, s.auctionBidsHandler)))
+	mux.HandleFunc("GET /users/{userAddress}/tokens", state(s.pagedJSON(defaultTokensLimit, maxTokensLimit, s.userTokensHandler)))
+	mux.HandleFunc("GET /users/{userAddress}/bids", state(s.pagedJSON(defaultBidsLimit, maxBidsLimit, s.userBidsHandler)))
+	mux.HandleFunc("GET /events", s.limited("events", s.eventsHandler)) // поток событий (SSE), ?auction=<id>&from=<блок>
+
+	// методы, требующие подписи
+	mux.HandleFunc("GET /balance", s.adminOnly(s.balanceHandler))                                                       // баланс кошелька backend
+	mux.HandleFunc("POST /notary-deposit/{userAddress}", s.limited("deposit", s.authenticated(s.notaryDepositHandler))) // пополнить НД, если его мало
+
+	return mux
+}
+
+// serveAPI serves the API until ctx is done and then gracefully shuts it down.
+func (s *Server) serveAPI(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s.newAPI(),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      time.Minute, // пополнение НД ждет принятия tx
+		IdleTimeout:       time.Minute,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.log.Info("shutting down API server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown API server: %w", err)
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// nonceHandler issues a nonce the user has to sign the next privileged request with.
+func (s *Server) nonceHandler(w http.ResponseWriter, r *http.Request) {
+	addr := r.PathValue("userAddress")
+	sh, err := address.StringToUint160(addr)
+	if err != nil {
+		s.log.Error("invalid user address", zap.String("address", addr), zap.Error(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// nonce может запросить кто угодно, поэтому лимит только по IP, лимит по адресу
+	// действует уже после проверки подписи, иначе чужие запросы исчерпывали бы его
+	n, err := s.auth.newNonce(sh, time.Now())
+	if err != nil {
+		s.log.Error("new nonce", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(nonceResponse{Nonce: hex.EncodeToString(n.data), ExpiresAt: n.expiresAt}); err != nil {
+		s.log.Error("write response error", zap.Error(err))
+	}
+}
+
+func (s *Server) balanceHandler(w http.ResponseWriter, _ *http.Request) {
+	s.log.Info("balance request")
+
+	res, err := s.gasAct.BalanceOf(s.acc.ScriptHash())
+	if err != nil {
+		s.log.Error("balance error", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write([]byte(strconv.FormatInt(res.Int64(), 10))); err != nil {
+		s.log.Error("write response error", zap.Error(err))
+	}
+}
+
+// propertiesHandler returns properties of the nft token.
+func (s *Server) propertiesHandler(w http.ResponseWriter, r *http.Request) {
+	s.log.Info("properties request")
+
+	tokenIDStr := r.PathValue("tokenID")
+	tokenID, err := hex.DecodeString(tokenIDStr)
+	if err != nil {
+		s.log.Error("invalid token ID", zap.String("tokenID", tokenIDStr), zap.Error(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	m, err := unwrap.Map(s.act.Call(s.nftHash(), "properties", tokenID))
+	if err != nil {
+		s.log.Error("call properties", zap.String("tokenID", tokenIDStr), zap.Error(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	props, err := parseMap(m)
+	if err != nil {
+		s.log.Error("parse properties", zap.String("tokenID", tokenIDStr), zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.Marshal(props)
+	if err != nil {
+		s.log.Error("parse properties", zap.String("tokenID", tokenIDStr), zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(data); err != nil {
+		s.log.Error("write response error", zap.Error(err))
+	}
+}
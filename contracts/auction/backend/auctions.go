@@ -0,0 +1,421 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/unwrap"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAuctionsLimit = 20
+	maxAuctionsLimit     = 100
+	defaultBidsLimit     = 50
+	maxBidsLimit         = 100 // столько же отдает контракт за один вызов
+	defaultTokensLimit   = 50
+	maxTokensLimit       = 100
+
+	maxStateCacheEntries = 1024 // ответы сверх лимита до следующего блока не кэшируются
+)
+
+var (
+	errNotFound   = errors.New("not found")
+	errBadRequest = errors.New("bad request")
+)
+
+type auctionResponse struct {
+	ID         int        `json:"id"`
+	Organizer  string     `json:"organizer"`
+	Lot        string     `json:"lot"`
+	InitialBet int64      `json:"initialBet"`
+	CurrentBet int64      `json:"currentBet"`
+	Winner     string     `json:"winner,omitempty"` // пусто, пока нет ставок
+	Domain     string     `json:"domain,omitempty"`
+	Finished   bool       `json:"finished"`
+	Bids       int64      `json:"bids"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// page is a parsed limit and offset of the list request.
+type page struct {
+	limit  int
+	offset int
+}
+
+type bidResponse struct {
+	Auction int64     `json:"auction"`
+	Bidder  string    `json:"bidder"`
+	Bet     int64     `json:"bet"`
+	Time    time.Time `json:"time"`
+}
+
+// blockCache keeps API responses until the next block, the state can't change earlier.
+type blockCache struct {
+	mu      sync.Mutex
+	height  uint32
+	entries map[string][]byte
+}
+
+func (c *blockCache) get(height uint32, key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if height != c.height {
+		return nil, false
+	}
+	data, ok := c.entries[key]
+	return data, ok
+}
+
+func (c *blockCache) put(height uint32, key string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if height != c.height || c.entries == nil { // новый блок - старые ответы больше не нужны
+		c.height = height
+		c.entries = make(map[string][]byte)
+	}
+	if len(c.entries) < maxStateCacheEntries {
+		c.entries[key] = data
+	}
+}
+
+// cachedJSON serves the value built from the chain state as JSON, responses are cached per block.
+func (s *Server) cachedJSON(build func(r *http.Request) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.serveCached(w, r, r.URL.Path, func() (any, error) { return build(r) })
+	}
+}
+
+// pagedJSON is cachedJSON for lists supporting limit and offset query parameters,
+// limit is defLimit by default and can't exceed maxLimit.
+func (s *Server) pagedJSON(defLimit, maxLimit int, build func(r *http.Request, p page) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, err := queryInt(r, "limit", defLimit)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		offset, err := queryInt(r, "offset", 0)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		p := page{limit: min(limit, maxLimit), offset: offset}
+
+		key := fmt.Sprintf("%s?limit=%d&offset=%d", r.URL.Path, p.limit, p.offset) // одинаковые страницы кэшируются один раз
+		s.serveCached(w, r, key, func() (any, error) { return build(r, p) })
+	}
+}
+
+func (s *Server) serveCached(w http.ResponseWriter, r *http.Request, key string, build func() (any, error)) {
+	height, err := s.rpcCli.GetBlockCount()
+	if err = rpcErr("getblockcount", err); err != nil {
+		s.log.Error("block count", zap.Error(err))
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	data, ok := s.stateCache.get(height, key)
+	if !ok {
+		v, err := build()
+		if err != nil {
+			status := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, errNotFound):
+				status = http.StatusNotFound
+			case errors.Is(err, errBadRequest):
+				status = http.StatusBadRequest
+			default:
+				s.log.Error("build response", zap.String("path", r.URL.Path), zap.Error(err))
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		if data, err = json.Marshal(v); err != nil {
+			s.log.Error("marshal response", zap.String("path", r.URL.Path), zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		s.stateCache.put(height, key, data)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(data); err != nil {
+		s.log.Error("write response error", zap.Error(err))
+	}
+}
+
+// auctionsHandler returns a page of auctions starting from the newest one.
+func (s *Server) auctionsHandler(_ *http.Request, p page) (any, error) {
+	lastID, err := unwrap.Int64(s.act.Call(s.auctionHash(), "lastAuctionID"))
+	if err != nil {
+		return nil, fmt.Errorf("last auction id: %w", err)
+	}
+
+	res := []auctionResponse{}
+	for id := int(lastID) - p.offset; id > 0 && len(res) < p.limit; id-- {
+		auc, err := s.getAuction(id)
+		if errors.Is(err, errNotFound) { // аукционы до обновления контракта не сохранены
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, auc)
+	}
+
+	return res, nil
+}
+
+func (s *Server) auctionHandler(r *http.Request) (any, error) {
+	id, err := pathAuctionID(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.getAuction(id)
+}
+
+func (s *Server) auctionBidsHandler(r *http.Request, p page) (any, error) {
+	id, err := pathAuctionID(r)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = s.getAuction(id); err != nil {
+		return nil, err
+	}
+	return s.getBids("getBids", id, p)
+}
+
+func (s *Server) userBidsHandler(r *http.Request, p page) (any, error) {
+	sh, err := pathUser(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.getBids("getUserBids", sh, p)
+}
+
+// userTokensHandler returns properties of a page of the user nft tokens, tokens
+// which properties can't be read are skipped.
+func (s *Server) userTokensHandler(r *http.Request, p page) (any, error) {
+	sh, err := pathUser(r)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := unwrap.ArrayOfBytes(s.act.Call(s.nftHash(), "tokensOfList", sh))
+	if err != nil {
+		return nil, fmt.Errorf("tokens of: %w", err)
+	}
+	ids = ids[min(p.offset, len(ids)):]
+	ids = ids[:min(p.limit, len(ids))]
+
+	res := make([]map[string]string, 0, len(ids))
+	for _, id := range ids {
+		props, err := s.tokenProperties(id)
+		if err != nil { // один сломанный токен не должен ломать весь список
+			s.log.Warn("skip token", zap.String("user", address.Uint160ToString(sh)),
+				zap.String("token", hex.EncodeToString(id)), zap.Error(err))
+			continue
+		}
+		res = append(res, props)
+	}
+
+	return res, nil
+}
+
+func (s *Server) tokenProperties(id []byte) (map[string]string, error) {
+	m, err := unwrap.Map(s.act.Call(s.nftHash(), "properties", id))
+	if err != nil {
+		return nil, fmt.Errorf("properties: %w", err)
+	}
+	props, err := parseMap(m)
+	if err != nil {
+		return nil, fmt.Errorf("parse properties: %w", err)
+	}
+	return props, nil
+}
+
+func (s *Server) getAuction(id int) (auctionResponse, error) {
+	inv, err := s.act.Call(s.auctionHash(), "getAuction", id)
+	if err = rpcErr("invokefunction", err); err != nil {
+		return auctionResponse{}, fmt.Errorf("get auction %d: %w", id, err)
+	}
+	if inv.State != vmstate.Halt.String() {
+		if strings.Contains(inv.FaultException, "auction not found") {
+			return auctionResponse{}, fmt.Errorf("auction %d: %w", id, errNotFound)
+		}
+		return auctionResponse{}, fmt.Errorf("get auction %d: %s", id, inv.FaultException)
+	}
+
+	item, err := unwrap.Item(inv, nil)
+	if err != nil {
+		return auctionResponse{}, fmt.Errorf("get auction %d: %w", id, err)
+	}
+	return parseAuction(item)
+}
+
+// getBids returns a page of bids.
+func (s *Server) getBids(method string, param any, p page) ([]bidResponse, error) {
+	if p.limit == 0 { // контракт не принимает нулевой limit
+		return []bidResponse{}, nil
+	}
+
+	items, err := unwrap.Array(s.act.Call(s.auctionHash(), method, param, p.offset, p.limit))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+
+	res := make([]bidResponse, 0, len(items))
+	for i, item := range items {
+		bid, err := parseBid(item)
+		if err != nil {
+			return nil, fmt.Errorf("bid %d: %w", i, err)
+		}
+		res = append(res, bid)
+	}
+
+	return res, nil
+}
+
+// parseAuction parses AuctionState struct of the auction contract.
+func parseAuction(item stackitem.Item) (auctionResponse, error) {
+	fields, ok := item.Value().([]stackitem.Item)
+	if !ok || len(fields) != 11 {
+		return auctionResponse{}, errors.New("invalid auction structure")
+	}
+
+	var (
+		res  auctionResponse
+		ints [7]int64
+	)
+	for i, idx := range []int{0, 3, 4, 7, 8, 9, 10} { // числовые поля, bool тоже приводится к числу
+		n, err := fields[idx].TryInteger()
+		if err != nil {
+			return auctionResponse{}, fmt.Errorf("field %d: %w", idx, err)
+		}
+		ints[i] = n.Int64()
+	}
+	res.ID = int(ints[0])
+	res.InitialBet, res.CurrentBet = ints[1], ints[2]
+	res.Finished = ints[3] != 0
+	res.Bids = ints[4]
+	res.StartedAt = time.UnixMilli(ints[5]).UTC()
+	if res.Finished {
+		t := time.UnixMilli(ints[6]).UTC()
+		res.FinishedAt = &t
+	}
+
+	organizer, err := stackAddress(fields[1])
+	if err != nil {
+		return auctionResponse{}, fmt.Errorf("organizer: %w", err)
+	}
+	res.Organizer = organizer
+
+	lot, err := fields[2].TryBytes()
+	if err != nil {
+		return auctionResponse{}, fmt.Errorf("lot: %w", err)
+	}
+	res.Lot = hex.EncodeToString(lot)
+
+	if _, isNull := fields[5].(stackitem.Null); !isNull {
+		if res.Winner, err = stackAddress(fields[5]); err != nil {
+			return auctionResponse{}, fmt.Errorf("winner: %w", err)
+		}
+	}
+
+	domain, err := fields[6].TryBytes()
+	if err != nil {
+		return auctionResponse{}, fmt.Errorf("domain: %w", err)
+	}
+	res.Domain = string(domain)
+
+	return res, nil
+}
+
+// parseBid parses Bid struct of the auction contract.
+func parseBid(item stackitem.Item) (bidResponse, error) {
+	fields, ok := item.Value().([]stackitem.Item)
+	if !ok || len(fields) != 4 {
+		return bidResponse{}, errors.New("invalid bid structure")
+	}
+
+	auctionID, err := fields[0].TryInteger()
+	if err != nil {
+		return bidResponse{}, fmt.Errorf("auction id: %w", err)
+	}
+	bidder, err := stackAddress(fields[1])
+	if err != nil {
+		return bidResponse{}, fmt.Errorf("bidder: %w", err)
+	}
+	bet, err := fields[2].TryInteger()
+	if err != nil {
+		return bidResponse{}, fmt.Errorf("bet: %w", err)
+	}
+	ts, err := fields[3].TryInteger()
+	if err != nil {
+		return bidResponse{}, fmt.Errorf("time: %w", err)
+	}
+
+	return bidResponse{
+		Auction: auctionID.Int64(),
+		Bidder:  bidder,
+		Bet:     bet.Int64(),
+		Time:    time.UnixMilli(ts.Int64()).UTC(),
+	}, nil
+}
+
+func stackAddress(item stackitem.Item) (string, error) {
+	data, err := item.TryBytes()
+	if err != nil {
+		return "", err
+	}
+	sh, err := util.Uint160DecodeBytesBE(data)
+	if err != nil {
+		return "", err
+	}
+	return address.Uint160ToString(sh), nil
+}
+
+func pathAuctionID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: invalid auction id %q", errBadRequest, r.PathValue("id"))
+	}
+	return id, nil
+}
+
+func pathUser(r *http.Request) (util.Uint160, error) {
+	sh, err := address.StringToUint160(r.PathValue("userAddress"))
+	if err != nil {
+		return util.Uint160{}, fmt.Errorf("%w: invalid address %q", errBadRequest, r.PathValue("userAddress"))
+	}
+	return sh, nil
+}
+
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: invalid %s %q", errBadRequest, name, s)
+	}
+	return n, nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"go.uber.org/zap"
+)
+
+const (
+	nftDomain     = "nft.auc"
+	auctionDomain = "auc.auc"
+
+	defaultNnsPollInterval = time.Minute
+)
+
+// contractHashes is a snapshot of contract hashes resolved via NNS, it's never
+// modified after creation, the whole snapshot is swapped instead.
+type contractHashes struct {
+	nft       util.Uint160
+	auction   util.Uint160
+	updatedAt time.Time
+}
+
+// contractsStatus is a response of the /status handler.
+type contractsStatus struct {
+	Nns       string    `json:"nns"`
+	Nft       string    `json:"nft"`
+	Auction   string    `json:"auction"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// nnsEvents are NNS notifications after which the resolution may change.
+var nnsEvents = map[string]struct{}{
+	"RecordAdded":      {},
+	"RecordDeleted":    {},
+	"DomainRegistered": {},
+	"DomainDeleted":    {},
+	"Transfer":         {},
+}
+
+func (s *Server) nftHash() util.Uint160 {
+	return s.hashes.Load().nft
+}
+
+func (s *Server) auctionHash() util.Uint160 {
+	return s.hashes.Load().auction
+}
+
+func (s *Server) resolveContractHashes() (*contractHashes, error) {
+	nftHash, err := ParseNnsResolve(nftDomain, s.nnsHash, s.act)
+	if err != nil {
+		return nil, err
+	}
+
+	auctionHash, err := ParseNnsResolve(auctionDomain, s.nnsHash, s.act)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contractHashes{nft: nftHash, auction: auctionHash, updatedAt: time.Now()}, nil
+}
+
+// refreshContractHashes resolves contract hashes once again and swaps them if something has changed.
+func (s *Server) refreshContractHashes(reason string) {
+	next, err := s.resolveContractHashes()
+	if err != nil { // во время передеплоя записи может временно не быть, оставляем старые хэши
+		s.log.Warn("resolve contract hashes", zap.String("reason", reason), zap.Error(err))
+		return
+	}
+
+	prev := s.hashes.Load()
+	if prev.nft.Equals(next.nft) && prev.auction.Equals(next.auction) {
+		return
+	}
+
+	s.hashes.Store(next)
+	s.log.Info("contract hashes updated", zap.String("reason", reason),
+		zap.Stringer("nft_old", prev.nft), zap.Stringer("nft", next.nft),
+		zap.Stringer("auction_old", prev.auction), zap.Stringer("auction", next.auction))
+}
+
+// runContractWatcher re-resolves contract hashes on NNS notifications and periodically,
+// in case some notification was missed.
+func (s *Server) runContractWatcher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-s.sub.NotificationChannels().NotificationsCh:
+			if !ok {
+				return
+			}
+			if !event.ScriptHash.Equals(s.nnsHash) {
+				continue
+			}
+			if _, ok = nnsEvents[event.Name]; !ok {
+				continue
+			}
+			s.refreshContractHashes("nns " + event.Name)
+		case <-ticker.C:
+			s.refreshContractHashes("poll")
+		}
+	}
+}
+
+func (s *Server) statusHandler(w http.ResponseWriter, _ *http.Request) {
+	hashes := s.hashes.Load()
+	data, err := json.Marshal(contractsStatus{
+		Nns:       s.nnsHash.StringLE(),
+		Nft:       hashes.nft.StringLE(),
+		Auction:   hashes.auction.StringLE(),
+		UpdatedAt: hashes.updatedAt,
+	})
+	if err != nil {
+		s.log.Error("marshal status", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(data); err != nil {
+		s.log.Error("write response error", zap.Error(err))
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/invoker"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/notary"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDepositThreshold = 0.5 // GAS
+	defaultDepositMinBlocks = 1000
+)
+
+// depositGuard keeps notary deposits from being topped up when it's not needed.
+type depositGuard struct {
+	threshold int64  // пополняем, если депозит меньше
+	minBlocks uint32 // или если он истекает раньше, чем через столько блоков
+
+	mu         sync.Mutex
+	depositing map[util.Uint160]chan struct{} // адреса, которые сейчас пополняются, канал закрывается по окончании
+}
+
+func newDepositGuard(threshold float64, minBlocks uint32) *depositGuard {
+	if threshold <= 0 {
+		threshold = defaultDepositThreshold
+	}
+	if minBlocks == 0 {
+		minBlocks = defaultDepositMinBlocks
+	}
+
+	return &depositGuard{
+		threshold:  int64(fixedn.Fixed8FromFloat(threshold)),
+		minBlocks:  minBlocks,
+		depositing: make(map[util.Uint160]chan struct{}),
+	}
+}
+
+// lockDeposit waits for the running top up of the address to finish, so the address
+// isn't topped up twice, and returns the function unlocking it. Top ups of
+// different addresses don't wait for each other.
+func (g *depositGuard) lockDeposit(sh util.Uint160) func() {
+	for {
+		g.mu.Lock()
+		done, ok := g.depositing[sh]
+		if !ok {
+			done = make(chan struct{})
+			g.depositing[sh] = done
+			g.mu.Unlock()
+
+			return func() {
+				g.mu.Lock()
+				delete(g.depositing, sh)
+				g.mu.Unlock()
+				close(done)
+			}
+		}
+		g.mu.Unlock()
+
+		<-done
+	}
+}
+
+// notaryDepositHandler tops up notary deposit of the user if it's low, the request
+// must be signed by the user himself.
+func (s *Server) notaryDepositHandler(w http.ResponseWriter, r *http.Request) {
+	addr := r.PathValue("userAddress")
+	s.log.Info("notary-deposit request", zap.String("address", addr), zap.String("ip", remoteIP(r)))
+
+	sh, err := address.StringToUint160(addr)
+	if err != nil {
+		s.log.Error("invalid user address", zap.String("address", addr), zap.Error(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if !sh.Equals(authUser(r)) { // пополнять можно только свой депозит
+		s.log.Warn("notary deposit for another user", zap.String("address", addr),
+			zap.String("user", address.Uint160ToString(authUser(r))))
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	if !s.limiter.allow(time.Now(), "deposit:addr:"+addr) {
+		s.log.Warn("notary deposit rate limited", zap.String("address", addr))
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+
+	if err = s.ensureNotaryDeposit(sh); err != nil {
+		s.log.Error("failed to notary deposit", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// ensureNotaryDeposit tops up notary deposit of the account only if it's below
+// the threshold or is going to expire soon.
+func (s *Server) ensureNotaryDeposit(sh util.Uint160) error {
+	defer s.deposits.lockDeposit(sh)()
+
+	r := notary.NewReader(invoker.New(s.rpcCli, nil))
+
+	balance, err := r.BalanceOf(sh)
+	if err != nil {
+		return fmt.Errorf("notary balance: %w", err)
+	}
+	till, err := r.ExpirationOf(sh)
+	if err != nil {
+		return fmt.Errorf("notary expiration: %w", err)
+	}
+	height, err := s.rpcCli.GetBlockCount()
+	if err = rpcErr("getblockcount", err); err != nil {
+		return fmt.Errorf("block count: %w", err)
+	}
+
+	if balance.Cmp(big.NewInt(s.deposits.threshold)) >= 0 && till >= height+s.deposits.minBlocks {
+		s.log.Debug("notary deposit is enough", zap.String("address", address.Uint160ToString(sh)),
+			zap.String("balance", fixedn.ToString(balance, 8)), zap.Uint32("till", till))
+		return nil
+	}
+
+	s.log.Info("top up notary deposit", zap.String("address", address.Uint160ToString(sh)),
+		zap.String("balance", fixedn.ToString(balance, 8)), zap.Uint32("till", till))
+	if err = s.notaryDeposit(sh); err != nil {
+		depositTopUps.WithLabelValues("failed").Inc()
+		return err
+	}
+	depositTopUps.WithLabelValues("success").Inc()
+	return nil
+}
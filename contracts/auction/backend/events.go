@@ -0,0 +1,534 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultEventsPollInterval  = time.Second
+	defaultEventsHistoryBlocks = 1000
+	defaultEventsSubscribers   = 1000
+
+	eventsKeepAlive   = 15 * time.Second
+	eventsSubQueueLen = 64
+
+	eventStarted  = "started"
+	eventBid      = "bid"
+	eventFinished = "finished"
+	eventTransfer = "transfer"
+)
+
+// chainEvent is an auction or nft notification decoded for API clients.
+type chainEvent struct {
+	ID      string    `json:"id"` // <блок>-<номер события в блоке>, по нему продолжается чтение после переподключения
+	Type    string    `json:"type"`
+	Auction int64     `json:"auction,omitempty"`
+	Block   uint32    `json:"block"`
+	Tx      string    `json:"tx"`
+	Time    time.Time `json:"time"`
+
+	Organizer  string `json:"organizer,omitempty"`
+	Bidder     string `json:"bidder,omitempty"`
+	Winner     string `json:"winner,omitempty"`
+	From       string `json:"from,omitempty"` // пусто при минте
+	To         string `json:"to,omitempty"`
+	Lot        string `json:"lot,omitempty"`
+	Domain     string `json:"domain,omitempty"`
+	InitialBet int64  `json:"initialBet,omitempty"`
+	Bet        int64  `json:"bet,omitempty"`
+
+	index int // номер события в блоке
+}
+
+// after reports whether the event goes after the given position in the chain.
+func (e chainEvent) after(block uint32, index int) bool {
+	return e.Block > block || e.Block == block && e.index > index
+}
+
+type eventSub struct {
+	auction int64 // 0 - все события
+	ch      chan chainEvent
+}
+
+func (sub *eventSub) match(e chainEvent) bool {
+	return sub.auction == 0 || e.Auction == sub.auction
+}
+
+// send queues matching events without blocking, it returns false if the subscriber lags behind.
+func (sub *eventSub) send(events []chainEvent) bool {
+	for _, e := range events {
+		if !sub.match(e) {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// eventHub keeps events of the last blocks and fans out new ones to subscribers.
+type eventHub struct {
+	mu            sync.Mutex
+	historyBlocks uint32
+	maxSubs       int
+	history       []chainEvent // события последних historyBlocks блоков по порядку
+	next          uint32       // следующий блок для индексации
+	subs          map[*eventSub]struct{}
+	closed        bool
+}
+
+func newEventHub(historyBlocks uint32, maxSubs int) *eventHub {
+	if historyBlocks == 0 {
+		historyBlocks = defaultEventsHistoryBlocks
+	}
+	if maxSubs <= 0 {
+		maxSubs = defaultEventsSubscribers
+	}
+
+	return &eventHub{
+		historyBlocks: historyBlocks,
+		maxSubs:       maxSubs,
+		subs:          make(map[*eventSub]struct{}),
+	}
+}
+
+// publish stores events of the indexed block and sends them to subscribers. Slow
+// subscribers are dropped, they can reconnect and resume from the last event id.
+func (h *eventHub) publish(height uint32, events []chainEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.next = height + 1
+	h.history = append(h.history, events...)
+	if height >= h.historyBlocks {
+		oldest := height - h.historyBlocks + 1
+		i := 0
+		for i < len(h.history) && h.history[i].Block < oldest {
+			i++
+		}
+		h.history = h.history[i:]
+	}
+
+	for sub := range h.subs {
+		if !sub.send(events) {
+			delete(h.subs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// subscribe registers a subscriber and returns stored events after the given position,
+// so that nothing is lost or duplicated between the replay and new events.
+func (h *eventHub) subscribe(auction int64, block uint32, index int) (*eventSub, []chainEvent, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, nil, errors.New("event hub is closed")
+	}
+	if len(h.subs) >= h.maxSubs {
+		return nil, nil, errors.New("too many subscribers")
+	}
+
+	sub := &eventSub{auction: auction, ch: make(chan chainEvent, eventsSubQueueLen)}
+	var replay []chainEvent
+	for _, e := range h.history {
+		if e.after(block, index) && sub.match(e) {
+			replay = append(replay, e)
+		}
+	}
+	h.subs[sub] = struct{}{}
+
+	return sub, replay, nil
+}
+
+func (h *eventHub) unsubscribe(sub *eventSub) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// close disconnects all subscribers, so that API server can shut down.
+func (h *eventHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for sub := range h.subs {
+		delete(h.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// runEventIndexer reads new blocks and publishes auction and nft events from them.
+// dBFT блоки финальны, поэтому откатывать уже отправленные события не нужно.
+func (s *Server) runEventIndexer(ctx context.Context, interval time.Duration) {
+	defer s.events.close()
+
+	count, err := s.rpcCli.GetBlockCount()
+	if err = rpcErr("getblockcount", err); err != nil {
+		s.log.Error("events: block count", zap.Error(err))
+	}
+	next := uint32(0)
+	if count > s.events.historyBlocks {
+		next = count - s.events.historyBlocks // история нужна, чтобы переподключившиеся клиенты ничего не потеряли
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		count, err = s.rpcCli.GetBlockCount()
+		if err = rpcErr("getblockcount", err); err != nil {
+			s.log.Error("events: block count", zap.Error(err))
+		}
+		for ; err == nil && next < count; next++ {
+			if ctx.Err() != nil {
+				return
+			}
+			var events []chainEvent
+			if events, err = s.blockEvents(next); err != nil {
+				s.log.Error("events: index block", zap.Uint32("block", next), zap.Error(err))
+				break // повторим этот блок на следующем тике
+			}
+			s.events.publish(next, events)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// blockEvents decodes auction and nft notifications of the block.
+func (s *Server) blockEvents(height uint32) ([]chainEvent, error) {
+	b, err := s.rpcCli.GetBlockByIndex(height)
+	if err = rpcErr("getblock", err); err != nil {
+		return nil, fmt.Errorf("get block: %w", err)
+	}
+
+	var (
+		res        []chainEvent
+		auc, nft   = s.auctionHash(), s.nftHash()
+		blockTS    = time.UnixMilli(int64(b.Timestamp)).UTC()
+		appTrigger = trigger.Application
+	)
+	for _, tx := range b.Transactions {
+		appLog, err := s.rpcCli.GetApplicationLog(tx.Hash(), &appTrigger)
+		if err = rpcErr("getapplicationlog", err); err != nil {
+			return nil, fmt.Errorf("application log of %s: %w", tx.Hash().StringLE(), err)
+		}
+
+		var txEvents []chainEvent
+		for _, exec := range appLog.Executions {
+			if exec.VMState != vmstate.Halt { // события упавших tx не применились
+				continue
+			}
+			for _, n := range exec.Events {
+				var (
+					e   *chainEvent
+					err error
+				)
+				switch {
+				case n.ScriptHash.Equals(auc):
+					e, err = decodeAuctionEvent(n)
+				case n.ScriptHash.Equals(nft) && n.Name == "Transfer":
+					e, err = decodeTransferEvent(n)
+				}
+				if err != nil {
+					s.log.Warn("events: decode notification", zap.String("tx", tx.Hash().StringLE()),
+						zap.String("name", n.Name), zap.Error(err))
+					continue
+				}
+				if e == nil {
+					continue
+				}
+				e.Block, e.Tx, e.Time = height, tx.Hash().StringLE(), blockTS
+				txEvents = append(txEvents, *e)
+			}
+		}
+		res = append(res, linkTransfers(txEvents)...)
+	}
+
+	for i := range res {
+		res[i].index = i
+		res[i].ID = strconv.FormatUint(uint64(height), 10) + "-" + strconv.Itoa(i)
+	}
+
+	return res, nil
+}
+
+// linkTransfers assigns auction id to nft transfers made by auction methods in the same tx,
+// so that they pass per-auction filter.
+func linkTransfers(events []chainEvent) []chainEvent {
+	lots := make(map[string]int64)
+	for _, e := range events {
+		if e.Type != eventTransfer && e.Lot != "" {
+			lots[e.Lot] = e.Auction
+		}
+	}
+	for i := range events {
+		if events[i].Type == eventTransfer {
+			events[i].Auction = lots[events[i].Lot]
+		}
+	}
+	return events
+}
+
+// decodeAuctionEvent decodes structured events of the auction contract, "info"
+// messages are skipped, they duplicate them.
+func decodeAuctionEvent(n state.NotificationEvent) (*chainEvent, error) {
+	var (
+		fields []stackitem.Item
+		e      chainEvent
+	)
+	switch n.Name {
+	case "AuctionStarted":
+		e.Type = eventStarted
+		fields = make([]stackitem.Item, 5)
+	case "BidMade":
+		e.Type = eventBid
+		fields = make([]stackitem.Item, 3)
+	case "AuctionFinished":
+		e.Type = eventFinished
+		fields = make([]stackitem.Item, 4)
+	default:
+		return nil, nil
+	}
+
+	items, ok := n.Item.Value().([]stackitem.Item)
+	if !ok || len(items) != len(fields) {
+		return nil, fmt.Errorf("%s: invalid parameters", n.Name)
+	}
+	copy(fields, items)
+
+	id, err := fields[0].TryInteger()
+	if err != nil {
+		return nil, fmt.Errorf("%s: id: %w", n.Name, err)
+	}
+	e.Auction = id.Int64()
+
+	acc, err := stackAddress(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("%s: account: %w", n.Name, err)
+	}
+
+	switch e.Type {
+	case eventStarted:
+		e.Organizer = acc
+		if e.Lot, err = stackHex(fields[2]); err != nil {
+			return nil, fmt.Errorf("%s: lot: %w", n.Name, err)
+		}
+		if e.InitialBet, err = stackInt(fields[3]); err != nil {
+			return nil, fmt.Errorf("%s: initial bet: %w", n.Name, err)
+		}
+		domain, err := fields[4].TryBytes()
+		if err != nil {
+			return nil, fmt.Errorf("%s: domain: %w", n.Name, err)
+		}
+		e.Domain = string(domain)
+	case eventBid:
+		e.Bidder = acc
+		if e.Bet, err = stackInt(fields[2]); err != nil {
+			return nil, fmt.Errorf("%s: bet: %w", n.Name, err)
+		}
+	case eventFinished:
+		e.Winner = acc
+		if e.Lot, err = stackHex(fields[2]); err != nil {
+			return nil, fmt.Errorf("%s: lot: %w", n.Name, err)
+		}
+		if e.Bet, err = stackInt(fields[3]); err != nil {
+			return nil, fmt.Errorf("%s: bet: %w", n.Name, err)
+		}
+	}
+
+	return &e, nil
+}
+
+// decodeTransferEvent decodes NEP-11 Transfer(from, to, amount, tokenId) of the nft contract.
+func decodeTransferEvent(n state.NotificationEvent) (*chainEvent, error) {
+	items, ok := n.Item.Value().([]stackitem.Item)
+	if !ok || len(items) != 4 {
+		return nil, errors.New("invalid transfer parameters")
+	}
+
+	var (
+		e   = chainEvent{Type: eventTransfer}
+		err error
+	)
+	if _, isNull := items[0].(stackitem.Null); !isNull {
+		if e.From, err = stackAddress(items[0]); err != nil {
+			return nil, fmt.Errorf("from: %w", err)
+		}
+	}
+	if _, isNull := items[1].(stackitem.Null); !isNull {
+		if e.To, err = stackAddress(items[1]); err != nil {
+			return nil, fmt.Errorf("to: %w", err)
+		}
+	}
+	if e.Lot, err = stackHex(items[3]); err != nil {
+		return nil, fmt.Errorf("token id: %w", err)
+	}
+
+	return &e, nil
+}
+
+func stackInt(item stackitem.Item) (int64, error) {
+	n, err := item.TryInteger()
+	if err != nil {
+		return 0, err
+	}
+	return n.Int64(), nil
+}
+
+func stackHex(item stackitem.Item) (string, error) {
+	data, err := item.TryBytes()
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(data), nil
+}
+
+// eventsHandler streams events as server-sent events. Supported query parameters:
+// auction - only events of this auction, from - start from this block height.
+// After a reconnect the stream continues after the Last-Event-ID header.
+func (s *Server) eventsHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		auction int64
+		block   uint32
+		index   = -1 // по умолчанию с первого события блока
+		err     error
+	)
+	if v := r.URL.Query().Get("auction"); v != "" {
+		if auction, err = strconv.ParseInt(v, 10, 64); err != nil || auction <= 0 {
+			http.Error(w, "invalid auction id", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := r.URL.Query().Get("from"); v != "" {
+		h, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			http.Error(w, "invalid block height", http.StatusBadRequest)
+			return
+		}
+		block = uint32(h)
+	} else {
+		block = s.events.head() // без from отдаем только новые события
+	}
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		if block, index, err = parseEventID(v); err != nil {
+			http.Error(w, "invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+	}
+
+	sub, replay, err := s.events.subscribe(auction, block, index)
+	if err != nil {
+		s.log.Warn("events subscription", zap.String("ip", remoteIP(r)), zap.Error(err))
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	defer s.events.unsubscribe(sub)
+
+	rc := http.NewResponseController(w)
+	if err = rc.SetWriteDeadline(time.Time{}); err != nil { // поток живет дольше WriteTimeout сервера
+		s.log.Error("events: reset write deadline", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // иначе nginx копит события в буфере
+	w.WriteHeader(http.StatusOK)
+
+	for _, e := range replay {
+		if err = writeEvent(w, e); err != nil {
+			return
+		}
+	}
+	if err = rc.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-sub.ch:
+			if !ok { // отстал или backend завершается, клиент переподключится с Last-Event-ID
+				return
+			}
+			err = writeEvent(w, e)
+		case <-keepAlive.C:
+			_, err = w.Write([]byte(": keep-alive\n\n"))
+		}
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			s.log.Debug("events: write", zap.String("ip", remoteIP(r)), zap.Error(err))
+			return
+		}
+	}
+}
+
+// head returns the next block to be indexed, its events aren't published yet.
+func (h *eventHub) head() uint32 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.next
+}
+
+func writeEvent(w http.ResponseWriter, e chainEvent) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
+
+func parseEventID(id string) (uint32, int, error) {
+	blockStr, indexStr, ok := strings.Cut(id, "-")
+	if !ok {
+		return 0, 0, errors.New("invalid event id")
+	}
+	block, err := strconv.ParseUint(blockStr, 10, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+	index, err := strconv.Atoi(indexStr)
+	if err != nil {
+		return 0, 0, err
+	}
+	return uint32(block), index, nil
+}
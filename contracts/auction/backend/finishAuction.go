@@ -1,16 +1,27 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 
-	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
-	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/notary"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"go.uber.org/zap"
 )
 
-func (s *Server) proceedMainTxFinishAuction(nAct *notary.Actor, notaryEvent *result.NotaryRequestEvent) error {
+var finishAuctionOperation = &operation{
+	contract: auctionContract,
+	method:   "finish",
+	validate: validateNotaryRequestFinishAuction,
+	check:    (*Server).checkNotaryRequestFinishAuction,
+	proceed:  (*Server).proceedMainTxFinishAuction,
+}
+
+func (s *Server) proceedMainTxFinishAuction(ctx context.Context, nAct *notary.Actor, r *notaryRequest) error {
+	notaryEvent := r.event
+
 	err := nAct.Sign(notaryEvent.NotaryRequest.MainTransaction)
 	if err != nil {
 		return fmt.Errorf("sign: %w", err)
@@ -22,7 +33,7 @@ func (s *Server) proceedMainTxFinishAuction(nAct *notary.Actor, notaryEvent *res
 		zap.String("main", mainHash.String()), zap.String("fb", fallbackHash.String()),
 		zap.Uint32("vub", vub))
 
-	_, err = nAct.Wait(mainHash, fallbackHash, vub, err)
+	_, err = s.waitNotarized(ctx, nAct, notaryEvent.NotaryRequest, mainHash, fallbackHash, vub, err)
 	if err != nil {
 		return fmt.Errorf("wait: %w", err)
 	}
@@ -30,30 +41,50 @@ func (s *Server) proceedMainTxFinishAuction(nAct *notary.Actor, notaryEvent *res
 	return nil
 }
 
-func validateNotaryRequestFinishAuction(req *payload.P2PNotaryRequest, s *Server) error {
-	args, contractHash, err := validateNotaryRequestPreProcessing(req)
+func validateNotaryRequestFinishAuction(r *notaryRequest) error {
+	if len(r.args) != 1 {
+		return fmt.Errorf("invalid param length: %d", len(r.args))
+	}
+
+	sh, err := util.Uint160DecodeBytesBE(r.args[0].Param())
 	if err != nil {
-		return err
+		return fmt.Errorf("could not decode script hash: %w", err)
 	}
 
-	contractHashExpected := s.auctionHash
+	r.user = sh
+	return nil
+}
 
-	if !contractHash.Equals(contractHashExpected) {
-		return fmt.Errorf("unexpected contract hash: %s", contractHash)
+func (s *Server) checkNotaryRequestFinishAuction(_ *notary.Actor, r *notaryRequest) (bool, error) {
+	res, reason, err := s.dryRun(r)
+	if err != nil {
+		return false, err
 	}
-
-	if len(args) != 1 {
-		return fmt.Errorf("invalid param length: %d", len(args))
+	if reason != "" {
+		return s.reject(r, reason)
 	}
 
-	_, err = util.Uint160DecodeBytesBE(args[0].Param())
+	winnerBytes, err := res.Stack[0].TryBytes()
 	if err != nil {
-		return fmt.Errorf("could not decode script hash: %w", err)
+		return s.reject(r, "finish returned no winner")
+	}
+	winner, err := util.Uint160DecodeBytesBE(winnerBytes)
+	if err != nil {
+		return s.reject(r, "invalid winner: "+err.Error())
 	}
 
-	return nil
-}
+	transfer := findNotification(res, s.nftHash(), "Transfer") // лот должен уйти победителю
+	if len(transfer) != 4 {
+		return s.reject(r, "no lot transfer")
+	}
+	to, err := transfer[1].TryBytes()
+	if err != nil || !bytes.Equal(to, winner.BytesBE()) {
+		return s.reject(r, "lot is not transferred to the winner")
+	}
+
+	if !hasInfo(res, r.contract, "Auction has been finished. Winner is: "+address.Uint160ToString(winner)) {
+		return s.reject(r, "no auction finish notification")
+	}
 
-func (s *Server) checkNotaryRequestFinishAuction(nAct *notary.Actor, finisher util.Uint160) (bool, error) {
 	return true, nil
 }
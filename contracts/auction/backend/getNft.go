@@ -1,43 +1,49 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net/http"
+	"time"
 
 	"git.frostfs.info/TrueCloudLab/frostfs-sdk-go/object"
 	"git.frostfs.info/TrueCloudLab/frostfs-sdk-go/pool"
 	"git.frostfs.info/TrueCloudLab/frostfs-sdk-go/user"
-	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
-	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/notary"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"go.uber.org/zap"
 )
 
-func validateNotaryRequestGetNft(req *payload.P2PNotaryRequest, s *Server) (util.Uint160, string, error) {
-	args, contractHash, err := validateNotaryRequestPreProcessing(req)
-	if err != nil {
-		return util.Uint160{}, "", err
-	}
-
-	contractHashExpected := s.nftHash
-
-	if !contractHash.Equals(contractHashExpected) {
-		return util.Uint160{}, "", fmt.Errorf("unexpected contract hash: %s", contractHash)
-	}
+var getNftOperation = &operation{
+	contract: nftContract,
+	method:   "mint",
+	validate: validateNotaryRequestGetNft,
+	check:    (*Server).checkNotaryRequestGetNft,
+	proceed:  (*Server).proceedMainTxGetNft,
+}
 
+func validateNotaryRequestGetNft(r *notaryRequest) error {
 	// аргументы лежат в обратном порядке (как мы их передаем, только наоборот)
-	if len(args) != 2 { // mint принимает ровно 2 аргумента
-		return util.Uint160{}, "", fmt.Errorf("invalid param length: %d", len(args))
+	if len(r.args) != 2 { // mint принимает ровно 2 аргумента
+		return fmt.Errorf("invalid param length: %d", len(r.args))
 	}
 
-	sh, err := util.Uint160DecodeBytesBE(args[1].Param())
+	sh, err := util.Uint160DecodeBytesBE(r.args[1].Param())
+	if err != nil {
+		return fmt.Errorf("could not decode script hash: %w", err)
+	}
 
-	return sh, string(args[0].Param()), err
+	r.user = sh
+	r.tokenName = string(r.args[0].Param())
+	return nil
 }
 
-func (s *Server) proceedMainTxGetNft(ctx context.Context, nAct *notary.Actor, notaryEvent *result.NotaryRequestEvent, tokenName string) error {
+func (s *Server) proceedMainTxGetNft(ctx context.Context, nAct *notary.Actor, r *notaryRequest) error {
+	notaryEvent, tokenName := r.event, r.tokenName
+
 	err := nAct.Sign(notaryEvent.NotaryRequest.MainTransaction)
 	if err != nil {
 		return fmt.Errorf("sign: %w", err)
@@ -49,16 +55,53 @@ func (s *Server) proceedMainTxGetNft(ctx context.Context, nAct *notary.Actor, no
 		zap.String("main", mainHash.String()), zap.String("fb", fallbackHash.String()),
 		zap.Uint32("vub", vub))
 
-	_, err = nAct.Wait(mainHash, fallbackHash, vub, err) // ждем, пока какая-нибудь tx будет принята
+	res, err := s.waitNotarized(ctx, nAct, notaryEvent.NotaryRequest, mainHash, fallbackHash, vub, err) // ждем, пока какая-нибудь tx будет принята
 	if err != nil {
 		return fmt.Errorf("wait: %w", err)
 	}
+	if !res.Container.Equals(notaryEvent.NotaryRequest.MainTransaction.Hash()) { // приняли fallback, токен не создан
+		return nil
+	}
+
+	return s.uploadTicket(ctx, notaryEvent.NotaryRequest.Hash(), tokenName)
+}
+
+// uploadTicket puts ticket metadata to FrostFS and sets its address to the minted token.
+// Both steps are journaled, so after a restart the metadata isn't uploaded twice. If it
+// fails, the ticket is picked up by the reconciler later.
+func (s *Server) uploadTicket(ctx context.Context, reqHash util.Uint256, tokenName string) error {
+	rec, err := s.journal.get(reqHash)
+	if err != nil {
+		return fmt.Errorf("journal: %w", err)
+	}
+
+	addr := rec.Address
+	if addr == "" {
+		if addr, err = s.putTicketMetaRetry(ctx, tokenName); err != nil {
+			return err
+		}
+		s.journalStep(reqHash, func(rec *journalRecord) {
+			rec.Step, rec.Address = stepUploaded, addr
+		})
+	}
 
+	return s.invokeNft(ctx, "setAddress", tokenName, addr) // добавляем адрес токену. После того, как произошел mint, заполнены у нового
+	// nft будут поля, кроме address. Он будет добавляться отдельно здесь, после того, как токен создался, потому что адрес frost fs ему присваивается только после
+	// помещения его вхранилище
+}
+
+// putTicketMeta fetches ticket json from the ticket API and puts it to FrostFS, it returns the object address.
+func (s *Server) putTicketMeta(ctx context.Context, tokenName string) (string, error) {
 	url := s.apiUrl + tokenName
 
-	resp, err := http.Get(url)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("new request '%s': %w", url, err)
+	}
+
+	resp, err := http.DefaultClient.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("get url '%s' : %w", url, err)
+		return "", fmt.Errorf("get url '%s' : %w", url, err)
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
@@ -66,6 +109,14 @@ func (s *Server) proceedMainTxGetNft(ctx context.Context, nAct *notary.Actor, no
 		}
 	}()
 
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound: // такого билета нет, повторять бесполезно
+		return "", fmt.Errorf("%w: %s", errMetadataNotFound, url)
+	default:
+		return "", fmt.Errorf("get url '%s': unexpected status %s", url, resp.Status)
+	}
+
 	// кладем json билета во frost fs
 
 	var ownerID user.ID
@@ -79,24 +130,37 @@ func (s *Server) proceedMainTxGetNft(ctx context.Context, nAct *notary.Actor, no
 	prm.SetPayload(resp.Body)
 	prm.SetHeader(*obj)
 
+	start := time.Now()
 	objID, err := s.p.PutObject(ctx, prm)
+	frostfsPutDuration.Observe(time.Since(start).Seconds())
 	if err != nil {
-		return fmt.Errorf("put object '%s': %w", url, err)
+		frostfsPutFailures.Inc()
+		return "", fmt.Errorf("put object '%s': %w", url, err)
 	}
 
 	addr := s.cnrID.EncodeToString() + "/" + objID.ObjectID.EncodeToString()
 	s.log.Info("put object", zap.String("url", url), zap.String("address", addr))
 
-	_, err = s.act.Wait(s.act.SendCall(s.nftHash, "setAddress", tokenName, addr)) // добавляем адрес токену. После того, как произошел mint, заполнены у нового
-	// nft будут поля, кроме address. Он будет добавляться отдельно здесь, после того, как токен создался, потому что адрес frost fs ему присваивается только после
-	// помещения его вхранилище
+	return addr, nil
+}
+
+func (s *Server) checkNotaryRequestGetNft(_ *notary.Actor, r *notaryRequest) (bool, error) {
+	res, reason, err := s.dryRun(r)
 	if err != nil {
-		return fmt.Errorf("wait setAddress: %w", err)
+		return false, err
+	}
+	if reason != "" {
+		return s.reject(r, reason)
 	}
 
-	return nil
-}
+	tokenID, err := res.Stack[0].TryBytes()
+	if err != nil {
+		return s.reject(r, "mint returned no token id")
+	}
+	expected := sha256.Sum256([]byte(r.tokenName)) // id токена - хэш его имени
+	if !bytes.Equal(tokenID, expected[:]) {
+		return s.reject(r, "unexpected token id "+hex.EncodeToString(tokenID))
+	}
 
-func (s *Server) checkNotaryRequestGetNft(nAct *notary.Actor, tokenName string) (bool, error) {
 	return true, nil
 }
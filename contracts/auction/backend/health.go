@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const healthCheckTimeout = 5 * time.Second
+
+// healthStatus is a response of health handlers, "ok" or the error for each component.
+type healthStatus map[string]string
+
+// healthzHandler reports whether the backend is alive. It fails only if the notary
+// subscription is lost, the backend doesn't reconnect it and has to be restarted.
+func (s *Server) healthzHandler(w http.ResponseWriter, _ *http.Request) {
+	var err error
+	if !s.notaryListening.Load() {
+		err = errors.New("notary subscription is closed")
+	}
+	s.writeHealth(w, healthStatus{"notary_subscription": healthString(err)}, err == nil)
+}
+
+// readyzHandler reports whether the backend can process requests now: RPC node,
+// WebSocket subscription and FrostFS pool must be reachable.
+func (s *Server) readyzHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+	defer cancel()
+
+	checks := map[string]func(context.Context) error{
+		"rpc": func(context.Context) error {
+			_, err := s.rpcCli.GetBlockCount()
+			return rpcErr("getblockcount", err)
+		},
+		"websocket": func(context.Context) error {
+			if !s.notaryListening.Load() {
+				return errors.New("notary subscription is closed")
+			}
+			_, err := s.wsCli.BlockCount()
+			return err
+		},
+		"frostfs": func(ctx context.Context) error {
+			_, err := s.p.NetworkInfo(ctx)
+			return err
+		},
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		status = make(healthStatus, len(checks))
+		ready  = true
+	)
+	for name, check := range checks { // проверки независимы, ждем только самую долгую
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			errCh := make(chan error, 1)
+			go func() { errCh <- check(ctx) }()
+
+			var err error
+			select {
+			case err = <-errCh:
+			case <-ctx.Done(): // rpc клиенты не принимают ctx, не ждем их дольше таймаута
+				err = ctx.Err()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			status[name] = healthString(err)
+			ready = ready && err == nil
+		}()
+	}
+	wg.Wait()
+
+	if !ready {
+		s.log.Warn("backend is not ready", zap.Any("status", status))
+	}
+	s.writeHealth(w, status, ready)
+}
+
+func (s *Server) writeHealth(w http.ResponseWriter, status healthStatus, ok bool) {
+	data, err := json.Marshal(status)
+	if err != nil {
+		s.log.Error("marshal health status", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if ok {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if _, err = w.Write(data); err != nil {
+		s.log.Error("write response error", zap.Error(err))
+	}
+}
+
+func healthString(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return "ok"
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/mempoolevent"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"go.etcd.io/bbolt"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultJournalPath      = "backend.db"
+	defaultJournalRetention = 7 * 24 * time.Hour
+	journalPruneInterval    = time.Hour
+
+	// шаги обработки нотариального запроса
+	stepReceived  = "received"  // запрос принят в обработку
+	stepNotarized = "notarized" // подписанная tx отправлена, ждем, какая попадет в блок
+	stepAccepted  = "accepted"  // main или fallback tx в блоке
+	stepUploaded  = "uploaded"  // метаданные билета во frostfs, осталось записать адрес в nft
+	stepDone      = "done"
+
+	// итоги обработки
+	outcomeMain     = "main"
+	outcomeFallback = "fallback"
+	outcomeRejected = "rejected"
+	outcomeFailed   = "failed"
+	outcomeExpired  = "expired" // backend не успел обработать запрос до истечения main tx
+)
+
+var journalBucket = []byte("notary_requests")
+
+// journalRecord is the state of the notary request processing.
+type journalRecord struct {
+	Method  string         `json:"method"`
+	User    string         `json:"user"`
+	Token   string         `json:"token,omitempty"`
+	Request []byte         `json:"request"` // сам запрос, чтобы обработать его заново после перезапуска
+	Step    string         `json:"step"`
+	Main    util.Uint256   `json:"main"`           // основная tx пользователя
+	Sent    []util.Uint256 `json:"sent,omitempty"` // отправленные backend tx, какая-то из них попадет в блок
+	VUB     uint32         `json:"vub,omitempty"`
+	Outcome string         `json:"outcome,omitempty"`
+	Address string         `json:"address,omitempty"` // адрес объекта frostfs с метаданными билета
+	Error   string         `json:"error,omitempty"`
+	Updated time.Time      `json:"updated"`
+}
+
+// journal is a local BoltDB log of notary requests, it keeps the backend from
+// processing a request twice and lets it finish incomplete requests after a restart.
+type journal struct {
+	db *bbolt.DB
+}
+
+func openJournal(path string) (*journal, error) {
+	if path == "" {
+		path = defaultJournalPath
+	}
+
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: time.Second}) // второй backend с тем же файлом не запустится
+	if err != nil {
+		return nil, fmt.Errorf("open journal %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(journalBucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("init journal: %w", err)
+	}
+
+	return &journal{db: db}, nil
+}
+
+func (j *journal) close() error {
+	return j.db.Close()
+}
+
+// begin records a new request, it returns false if the request is already known.
+func (j *journal) begin(r *notaryRequest) (bool, error) {
+	data, err := r.event.NotaryRequest.Bytes()
+	if err != nil {
+		return false, fmt.Errorf("encode notary request: %w", err)
+	}
+
+	rec := journalRecord{
+		Method:  r.op.method,
+		User:    address.Uint160ToString(r.user),
+		Token:   r.tokenName,
+		Request: data,
+		Step:    stepReceived,
+		Main:    r.event.NotaryRequest.MainTransaction.Hash(),
+		Updated: time.Now(),
+	}
+
+	created := false
+	err = j.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(journalBucket)
+		key := r.event.NotaryRequest.Hash().BytesBE()
+		if b.Get(key) != nil {
+			return nil
+		}
+		created = true
+		return putRecord(b, key, rec)
+	})
+	return created, err
+}
+
+// update modifies the record of the request.
+func (j *journal) update(hash util.Uint256, f func(*journalRecord)) error {
+	return j.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(journalBucket)
+		key := hash.BytesBE()
+		rec, err := getRecord(b, key)
+		if err != nil {
+			return err
+		}
+		f(&rec)
+		rec.Updated = time.Now()
+		return putRecord(b, key, rec)
+	})
+}
+
+func (j *journal) get(hash util.Uint256) (journalRecord, error) {
+	var rec journalRecord
+	err := j.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		rec, err = getRecord(tx.Bucket(journalBucket), hash.BytesBE())
+		return err
+	})
+	return rec, err
+}
+
+// incomplete returns requests which processing was interrupted.
+func (j *journal) incomplete() (map[util.Uint256]journalRecord, error) {
+	res := make(map[util.Uint256]journalRecord)
+	err := j.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(journalBucket).ForEach(func(k, v []byte) error {
+			var rec journalRecord
+			if err := json.Unmarshal(v, &rec); err != nil {
+				return fmt.Errorf("decode record %x: %w", k, err)
+			}
+			if rec.Step == stepDone {
+				return nil
+			}
+			h, err := util.Uint256DecodeBytesBE(k)
+			if err != nil {
+				return err
+			}
+			res[h] = rec
+			return nil
+		})
+	})
+	return res, err
+}
+
+// pendingTokens returns names of tickets which mint requests are still being processed.
+func (j *journal) pendingTokens() (map[string]struct{}, error) {
+	recs, err := j.incomplete()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]struct{})
+	for _, rec := range recs {
+		if rec.Method == getNftOperation.method {
+			res[rec.Token] = struct{}{}
+		}
+	}
+	return res, nil
+}
+
+// ticketAddress returns the address of ticket metadata uploaded while processing
+// its mint request, if any.
+func (j *journal) ticketAddress(token string) (string, error) {
+	var addr string
+	err := j.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(journalBucket).ForEach(func(k, v []byte) error {
+			var rec journalRecord
+			if err := json.Unmarshal(v, &rec); err != nil {
+				return fmt.Errorf("decode record %x: %w", k, err)
+			}
+			if rec.Method == getNftOperation.method && rec.Token == token && rec.Address != "" {
+				addr = rec.Address
+			}
+			return nil
+		})
+	})
+	return addr, err
+}
+
+// prune removes completed requests updated before the given time.
+func (j *journal) prune(before time.Time) (int, error) {
+	var n int
+	err := j.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(journalBucket)
+		var keys [][]byte
+		err := b.ForEach(func(k, v []byte) error {
+			var rec journalRecord
+			if err := json.Unmarshal(v, &rec); err != nil {
+				return fmt.Errorf("decode record %x: %w", k, err)
+			}
+			if rec.Step == stepDone && rec.Updated.Before(before) {
+				keys = append(keys, k)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range keys { // удалять внутри ForEach нельзя
+			if err = b.Delete(k); err != nil {
+				return err
+			}
+		}
+		n = len(keys)
+		return nil
+	})
+	return n, err
+}
+
+func getRecord(b *bbolt.Bucket, key []byte) (journalRecord, error) {
+	var rec journalRecord
+	data := b.Get(key)
+	if data == nil {
+		return rec, fmt.Errorf("journal record %x not found", key)
+	}
+	return rec, json.Unmarshal(data, &rec)
+}
+
+func putRecord(b *bbolt.Bucket, key []byte, rec journalRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, data)
+}
+
+// journalStep moves the request to the next step, errors are only logged: the
+// request is already being processed and the journal mustn't break it.
+func (s *Server) journalStep(hash util.Uint256, f func(*journalRecord)) {
+	if err := s.journal.update(hash, f); err != nil {
+		s.log.Error("update journal", zap.String("hash", hash.StringLE()), zap.Error(err))
+	}
+}
+
+// finishRequest records the outcome of the request processing and returns it. If some
+// transaction was accepted, its outcome written by waitNotarized is kept: backend could
+// choose the main transaction, but the fallback one could get into the block.
+func (s *Server) finishRequest(hash util.Uint256, outcome string, err error) string {
+	s.journalStep(hash, func(rec *journalRecord) {
+		rec.Step = stepDone
+		if rec.Outcome == "" {
+			rec.Outcome = outcome
+		}
+		outcome = rec.Outcome
+		if err != nil {
+			rec.Error = err.Error()
+		}
+	})
+	return outcome
+}
+
+// resumeJournal finishes requests which processing was interrupted by the backend restart.
+func (s *Server) resumeJournal(ctx context.Context) {
+	recs, err := s.journal.incomplete()
+	if err != nil {
+		s.log.Error("read journal", zap.Error(err))
+		return
+	}
+	if len(recs) != 0 {
+		s.log.Info("resume notary requests", zap.Int("count", len(recs)))
+	}
+
+	for hash, rec := range recs {
+		if ctx.Err() != nil {
+			return
+		}
+
+		log := s.log.With(zap.String("hash", hash.StringLE()), zap.String("method", rec.Method), zap.String("step", rec.Step))
+		outcome, err := s.resumeRequest(ctx, hash, rec)
+		if ctx.Err() != nil { // остановились снова, продолжим при следующем запуске
+			return
+		}
+		if outcome == "" { // запрос снова в очереди воркеров
+			log.Info("notary request requeued")
+			continue
+		}
+		if err != nil {
+			log.Error("resume notary request", zap.Error(err))
+		} else {
+			log.Info("notary request resumed", zap.String("outcome", outcome))
+		}
+		s.finishRequest(hash, outcome, err)
+	}
+}
+
+// resumeRequest continues the request from its last step. Empty outcome means the
+// request was queued for processing from the beginning.
+func (s *Server) resumeRequest(ctx context.Context, hash util.Uint256, rec journalRecord) (string, error) {
+	outcome := rec.Outcome
+
+	switch rec.Step {
+	case stepReceived: // подписать не успели, запрос можно обработать заново, пока main tx не истекла
+		nr, err := payload.NewP2PNotaryRequestFromBytes(rec.Request)
+		if err != nil {
+			return outcomeFailed, fmt.Errorf("decode notary request: %w", err)
+		}
+		height, err := s.rpcCli.GetBlockCount()
+		if err = rpcErr("getblockcount", err); err != nil {
+			return outcomeFailed, err
+		}
+		if nr.MainTransaction.ValidUntilBlock < height {
+			return outcomeExpired, nil
+		}
+
+		req, err := s.parseNotaryRequest(notaryEventFromRequest(nr))
+		if err != nil {
+			return outcomeFailed, fmt.Errorf("parse notary request: %w", err)
+		}
+		s.notaryPool.push(ctx, req)
+		return "", nil
+	case stepNotarized: // tx отправлены, дожидаемся любой из них
+		nr, err := payload.NewP2PNotaryRequestFromBytes(rec.Request)
+		if err != nil {
+			return outcomeFailed, fmt.Errorf("decode notary request: %w", err)
+		}
+		res, err := s.act.WaitAny(ctx, rec.VUB, rec.Sent...)
+		if err != nil {
+			return outcomeFailed, fmt.Errorf("wait: %w", err)
+		}
+		outcome = recordAccepted(nr.MainTransaction, res)
+		s.journalStep(hash, func(r *journalRecord) {
+			r.Step, r.Outcome = stepAccepted, outcome
+		})
+	}
+
+	if rec.Method == getNftOperation.method && outcome == outcomeMain { // токен создан, но адреса метаданных у него еще может не быть
+		if err := s.uploadTicket(ctx, hash, rec.Token); err != nil {
+			return outcomeFailed, err
+		}
+	}
+
+	return outcome, nil
+}
+
+// runJournalPruner periodically removes old completed requests from the journal.
+func (s *Server) runJournalPruner(ctx context.Context, retention time.Duration) {
+	t := time.NewTicker(journalPruneInterval)
+	defer t.Stop()
+
+	for {
+		n, err := s.journal.prune(time.Now().Add(-retention))
+		if err != nil {
+			s.log.Error("prune journal", zap.Error(err))
+		} else if n != 0 {
+			s.log.Debug("journal pruned", zap.Int("removed", n))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func notaryEventFromRequest(nr *payload.P2PNotaryRequest) *result.NotaryRequestEvent {
+	return &result.NotaryRequestEvent{Type: mempoolevent.TransactionAdded, NotaryRequest: nr}
+}
@@ -6,16 +6,15 @@ import (
 	"crypto/elliptic"
 	"encoding/binary"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
 	"math/big"
-	"net/http"
 	"os"
 	"os/signal"
 	"runtime/debug"
-	"strconv"
+	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -24,13 +23,12 @@ import (
 	"git.frostfs.info/TrueCloudLab/frostfs-node/pkg/util/logger"
 	cid "git.frostfs.info/TrueCloudLab/frostfs-sdk-go/container/id"
 	"git.frostfs.info/TrueCloudLab/frostfs-sdk-go/pool"
-	"git.frostfs.info/TrueCloudLab/frostfs-sdk-go/user"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
 	"github.com/nspcc-dev/neo-go/pkg/core/mempoolevent"
 	"github.com/nspcc-dev/neo-go/pkg/core/native"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
-	"github.com/nspcc-dev/neo-go/pkg/encoding/base58"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
@@ -40,6 +38,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/nep17"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/notary"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/unwrap"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/waiter"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
@@ -60,10 +59,30 @@ const (
 	cfgStorageContainer = "storage_container"
 	cfgListenAddress    = "listen_address"
 	cfgTicketApiUrl     = "ticket_api_url"
+	cfgNnsPollInterval  = "nns_poll_interval"
+	cfgRenewDomains     = "renew_domains"
+	cfgRenewBefore      = "renew_before"
+	cfgRenewInterval    = "renew_check_interval"
+	cfgNotaryWorkers    = "notary_workers"
+	cfgNotaryQueueSize  = "notary_queue_size"
+	cfgNotaryTimeout    = "notary_request_timeout"
+	cfgDepositThreshold = "notary_deposit_threshold"
+	cfgDepositMinBlocks = "notary_deposit_min_blocks"
+	cfgAPIRateLimit     = "api_rate_limit"
+	cfgStateRateLimit   = "state_rate_limit"
+	cfgAPIAdmins        = "api_admins"
+	cfgEventsInterval   = "events_poll_interval"
+	cfgEventsHistory    = "events_history_blocks"
+	cfgEventsMaxSubs    = "events_max_subscribers"
+	cfgJournalPath      = "journal_path"
+	cfgJournalRetention = "journal_retention"
+	cfgMetadataRetries  = "metadata_retries"
+	cfgMetadataBackoff  = "metadata_retry_backoff"
+	cfgMetadataAttempts = "metadata_max_attempts"
+	cfgMetadataFailure  = "metadata_failure_policy"
+	cfgReconcileInt     = "reconcile_interval"
 )
 
-var currentOperation = ""
-
 func main() {
 	ctx, _ := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM) // если пользователь нажмет ctrl+C, то завершим выполнение
 
@@ -78,28 +97,45 @@ func main() {
 	die(viper.GetViper().ReadConfig(f)) // считываем
 	die(f.Close())                      // закрываем
 
-	s, err := NewServer(ctx)
+	s, err := NewServer(ctx, os.Args[1])
 	die(err)
 
 	die(s.Listen(ctx))
+	die(s.journal.close())
 }
 
 type Server struct {
-	p           *pool.Pool      // пул = обертка над клиентом, который умеет работать со storage node
-	acc         *wallet.Account // кошелек, который будет платить за транзакции (вместо клиентского кошелька)
-	act         *actor.Actor
-	gasAct      *nep17.Token
-	nftHash     util.Uint160
-	auctionHash util.Uint160
-	nnsHash     util.Uint160
-	cnrID       cid.ID // Id контейнера в frost fs для хранения данных
-	log         *zap.Logger
-	rpcCli      *rpcclient.Client
-	sub         subscriber.Subscriber // подписчик на события bc
-	apiUrl      string
+	p       *pool.Pool      // пул = обертка над клиентом, который умеет работать со storage node
+	acc     *wallet.Account // кошелек, который будет платить за транзакции (вместо клиентского кошелька)
+	act     *actor.Actor
+	gasAct  *nep17.Token
+	hashes  atomic.Pointer[contractHashes] // хэши nft и auction, перечитываются из nns после передеплоя
+	nnsHash util.Uint160
+	cnrID   cid.ID // Id контейнера в frost fs для хранения данных
+	log     *zap.Logger
+	rpcCli  *rpcclient.Client
+	sub     subscriber.Subscriber // подписчик на события bc
+	apiUrl  string
+	ops     operationRegistry // обработчики поддерживаемых методов контрактов
+
+	notaryPool *notaryPool                   // воркеры, которые подписывают и отправляют НЗ
+	policy     atomic.Pointer[sponsorPolicy] // политика спонсирования, перечитывается по SIGHUP
+	usage      sponsorUsage                  // сколько уже потрачено на спонсирование
+	cfgPath    string
+	deposits   *depositGuard  // защита от многократного пополнения НД
+	auth       *authenticator // проверка подписанных запросов к API
+	limiter    *rateLimiter
+	readRate   *rateLimiter // отдельный лимит для чтения состояния аукционов
+	stateCache blockCache   // ответы API о состоянии аукционов
+	events     *eventHub    // события аукционов и nft для клиентов API
+
+	journal         *journal            // шаги обработки НЗ, переживает перезапуск
+	metadata        metadataPolicy      // повторы загрузки метаданных билетов
+	wsCli           *morphclient.Client // клиент подписки, нужен для проверки ее соединения
+	notaryListening atomic.Bool         // подписка на НЗ жива
 }
 
-func NewServer(ctx context.Context) (*Server, error) {
+func NewServer(ctx context.Context, cfgPath string) (*Server, error) {
 	rpcCli, err := rpcclient.New(ctx, viper.GetString(cfgRPCEndpoint), rpcclient.Options{}) // создание rpc клиента взаимодействия приложений
 	// или пользователей с нодой bc, rpc_endpoint = "http://localhost:30333"
 	if err != nil {
@@ -131,16 +167,6 @@ func NewServer(ctx context.Context) (*Server, error) {
 		return nil, err
 	}
 
-	contractNftHash, err := ParseNnsResolve("nft.auc", contractNnsHash, act)
-	if err != nil {
-		return nil, err
-	}
-
-	contractAuctionHash, err := ParseNnsResolve("auc.auc", contractNnsHash, act)
-	if err != nil {
-		return nil, err
-	}
-
 	ticketApiUrl := viper.GetString(cfgTicketApiUrl)
 
 	var cnrID cid.ID
@@ -176,158 +202,136 @@ func NewServer(ctx context.Context) (*Server, error) {
 		return nil, err
 	}
 
+	if err = sub.SubscribeForNotification(contractNnsHash); err != nil { // и на события nns, чтобы узнавать о передеплое контрактов
+		return nil, err
+	}
+
 	log, err := zap.NewDevelopment() // создание логгера
 	if err != nil {
 		return nil, err
 	}
 
-	return &Server{
-		p:           p,
-		acc:         acc,
-		act:         act,
-		rpcCli:      rpcCli,
-		nftHash:     contractNftHash,
-		auctionHash: contractAuctionHash,
-		nnsHash:     contractNnsHash,
-		gasAct:      nep17.New(act, gas.Hash),
-		cnrID:       cnrID,
-		log:         log,
-		sub:         sub,
-		apiUrl:      ticketApiUrl,
-	}, nil
-}
-
-func ParseNnsResolve(domainName string, contractNnsHash util.Uint160, act *actor.Actor) (util.Uint160, error) {
-	res, _ := act.Call(contractNnsHash, "resolve", domainName, 16)
-	item := res.Stack[0]
-	var output []string
-	switch v := item.Value().(type) {
-	case []stackitem.Item:
-		for _, innerItem := range v {
-			if innerBytes, ok := innerItem.Value().([]byte); ok { // Проверяем, является ли элемент ByteString
-				output = append(output, string(innerBytes)) // Преобразуем ByteString в строку
-			} else {
-				return util.Uint160{}, fmt.Errorf("unexpected type in nns resolve "+domainName+" output array item: %T", innerItem.Value())
-			}
-		}
-	default:
-		return util.Uint160{}, fmt.Errorf("unexpected type in nns resolve "+domainName+" output array: %T", v)
-	}
-	decoded, err := base58.CheckDecode(output[0])
+	s := &Server{
+		p:        p,
+		acc:      acc,
+		act:      act,
+		rpcCli:   rpcCli,
+		nnsHash:  contractNnsHash,
+		gasAct:   nep17.New(act, gas.Hash),
+		cnrID:    cnrID,
+		log:      log,
+		sub:      sub,
+		wsCli:    neoClient,
+		apiUrl:   ticketApiUrl,
+		ops:      newOperationRegistry(getNftOperation, startAuctionOperation, makeBetOperation, finishAuctionOperation),
+		cfgPath:  cfgPath,
+		deposits: newDepositGuard(viper.GetFloat64(cfgDepositThreshold), viper.GetUint32(cfgDepositMinBlocks)),
+		limiter:  newRateLimiter(viper.GetInt(cfgAPIRateLimit)),
+		readRate: newRateLimiter(viper.GetInt(cfgStateRateLimit)),
+		events:   newEventHub(viper.GetUint32(cfgEventsHistory), viper.GetInt(cfgEventsMaxSubs)),
+	}
+
+	admins, err := parseAddressSet(viper.GetStringSlice(cfgAPIAdmins))
 	if err != nil {
-		return util.Uint160{}, err
+		return nil, fmt.Errorf("api admins: %w", err)
 	}
-	contractHashStr := hex.EncodeToString(decoded)[2:]
-	contractHash, err := util.Uint160DecodeStringBE(contractHashStr)
+	s.auth = newAuthenticator(admins)
+
+	policy, err := loadSponsorPolicy(viper.GetViper())
 	if err != nil {
-		return util.Uint160{}, err
+		return nil, err
 	}
+	s.policy.Store(policy)
 
-	return contractHash, nil
-}
-
-func (s *Server) Listen(ctx context.Context) error {
-	if err := s.notaryDeposit(s.acc.ScriptHash()); err != nil { // накидываем себе (серверу) НД
-		return fmt.Errorf("notary backend deposit: %w", err)
+	hashes, err := s.resolveContractHashes()
+	if err != nil {
+		return nil, err
 	}
+	s.hashes.Store(hashes)
 
-	go s.runNotaryValidator(ctx) // // запускается слушатель нотариальных запросов в отдельной горутине (фоновый процесс)
-
-	// обработчики запросов, которые слушают на 5555
+	s.notaryPool = newNotaryPool(s, viper.GetInt(cfgNotaryWorkers), viper.GetInt(cfgNotaryQueueSize),
+		viper.GetDuration(cfgNotaryTimeout))
 
-	http.DefaultServeMux.HandleFunc("/balance", func(w http.ResponseWriter, r *http.Request) {
-		s.log.Info("balance request")
-
-		res, err := s.gasAct.BalanceOf(s.acc.ScriptHash())
-		if err != nil {
-			s.log.Error("balance error", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
+	s.metadata, err = newMetadataPolicy(viper.GetInt(cfgMetadataRetries), viper.GetDuration(cfgMetadataBackoff),
+		viper.GetInt(cfgMetadataAttempts), viper.GetString(cfgMetadataFailure))
+	if err != nil {
+		return nil, err
+	}
 
-		w.WriteHeader(http.StatusOK)
-		if _, err = w.Write([]byte(strconv.FormatInt(res.Int64(), 10))); err != nil {
-			s.log.Error("write response error", zap.Error(err))
-		}
-	})
+	if s.journal, err = openJournal(viper.GetString(cfgJournalPath)); err != nil {
+		return nil, err
+	}
 
-	http.DefaultServeMux.HandleFunc("/properties/{tokenID}", func(w http.ResponseWriter, r *http.Request) { // обработчик запроса "посмотреть свойства указанного nft токена"
-		s.log.Info("properties request")
+	return s, nil
+}
 
-		tokenIDStr := r.PathValue("tokenID")
-		tokenID, err := hex.DecodeString(tokenIDStr)
-		if err != nil {
-			s.log.Error("invalid token ID", zap.String("tokenID", tokenIDStr), zap.Error(err))
-			w.WriteHeader(http.StatusBadRequest)
-			return
-		}
+func ParseNnsResolve(domainName string, contractNnsHash util.Uint160, act *actor.Actor) (util.Uint160, error) {
+	contractHash, err := unwrap.Uint160(act.Call(contractNnsHash, "resolveHash", domainName)) // HASH160 запись nns сразу отдает хэш контракта
+	if err != nil {
+		return util.Uint160{}, fmt.Errorf("nns resolve %s: %w", domainName, err)
+	}
 
-		m, err := unwrap.Map(s.act.Call(s.nftHash, "properties", tokenID))
-		if err != nil {
-			s.log.Error("call properties", zap.String("tokenID", tokenIDStr), zap.Error(err))
-			w.WriteHeader(http.StatusBadRequest)
-			return
-		}
+	return contractHash, nil
+}
 
-		props, err := parseMap(m)
-		if err != nil {
-			s.log.Error("parse properties", zap.String("tokenID", tokenIDStr), zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
+func (s *Server) Listen(ctx context.Context) error {
+	if err := s.ensureNotaryDeposit(s.acc.ScriptHash()); err != nil { // накидываем себе (серверу) НД, если его мало
+		return fmt.Errorf("notary backend deposit: %w", err)
+	}
 
-		data, err := json.Marshal(props)
-		if err != nil {
-			s.log.Error("parse properties", zap.String("tokenID", tokenIDStr), zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
+	s.notaryPool.start(ctx)
+	go s.resumeJournal(ctx) // дообрабатываем запросы, прерванные прошлой остановкой
 
-		w.WriteHeader(http.StatusOK)
-		if _, err = w.Write(data); err != nil {
-			s.log.Error("write response error", zap.Error(err))
-		}
-	})
+	retention := viper.GetDuration(cfgJournalRetention)
+	if retention <= 0 {
+		retention = defaultJournalRetention
+	}
+	go s.runJournalPruner(ctx, retention)
 
-	http.DefaultServeMux.HandleFunc("/notary-deposit/{userAddress}", func(w http.ResponseWriter, r *http.Request) { // накинуть НД по нужному адресу (клиент
-		// этот запрос дергает, чтобы себе получить НД)
-		s.log.Info("notary-deposit request", zap.String("url", r.URL.String()))
+	reconcileInterval := viper.GetDuration(cfgReconcileInt)
+	if reconcileInterval <= 0 {
+		reconcileInterval = defaultReconcileInterval
+	}
+	go s.runTicketReconciler(ctx, reconcileInterval) // дозагружаем метаданные билетов, у которых нет адреса
 
-		var userID user.ID
-		err := userID.DecodeString(r.PathValue("userAddress"))
-		if err != nil {
-			s.log.Error("invalid user address", zap.String("address", r.PathValue("userAddress")), zap.Error(err))
-			w.WriteHeader(http.StatusBadRequest)
-			return
-		}
+	go s.runPolicyReloader(ctx, s.cfgPath) // политику спонсирования можно поменять без перезапуска
+	go s.runNotaryValidator(ctx)           // // запускается слушатель нотариальных запросов в отдельной горутине (фоновый процесс)
 
-		sh, err := userID.ScriptHash()
-		if err != nil {
-			s.log.Error("invalid user script hash", zap.Error(err))
-			w.WriteHeader(http.StatusBadRequest)
-			return
-		}
+	pollInterval := viper.GetDuration(cfgNnsPollInterval)
+	if pollInterval <= 0 {
+		pollInterval = defaultNnsPollInterval
+	}
+	go s.runContractWatcher(ctx, pollInterval) // следим за изменением записей nns
 
-		if err = s.notaryDeposit(sh); err != nil {
-			s.log.Error("failed to notary deposit", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
+	renewBefore := viper.GetDuration(cfgRenewBefore)
+	if renewBefore <= 0 {
+		renewBefore = defaultRenewBefore
+	}
+	renewInterval := viper.GetDuration(cfgRenewInterval)
+	if renewInterval <= 0 {
+		renewInterval = defaultRenewCheckInterval
+	}
+	go s.runDomainRenewer(ctx, viper.GetStringSlice(cfgRenewDomains), renewBefore, renewInterval) // продлеваем домены заранее
 
-		w.WriteHeader(http.StatusOK)
-	})
+	eventsInterval := viper.GetDuration(cfgEventsInterval)
+	if eventsInterval <= 0 {
+		eventsInterval = defaultEventsPollInterval
+	}
+	go s.runEventIndexer(ctx, eventsInterval) // рассылаем события аукционов клиентам
 
-	return http.ListenAndServe(viper.GetString(cfgListenAddress), nil)
+	return s.serveAPI(ctx, viper.GetString(cfgListenAddress)) // API слушает на 5555 и останавливается вместе с ctx
 }
 
 func (s *Server) runNotaryValidator(ctx context.Context) { // слушатель НЗ из bc
 
 	s.log.Info("start listening")
+	s.notaryListening.Store(true)
+	defer s.notaryListening.Store(false) // канал подписки закрылся, без перезапуска НЗ больше не придут
 
 	for {
 		select {
-		case <-ctx.Done():
-			die(ctx.Err())
+		case <-ctx.Done(): // завершение по сигналу, API в это время останавливается
+			return
 		case notaryEvent, ok := <-s.sub.NotificationChannels().NotaryRequestsCh: // ждем события из канала NotaryRequestsCh,
 			// который предоставляет уведомления о нотариальных запросах
 			if !ok {
@@ -337,139 +341,107 @@ func (s *Server) runNotaryValidator(ctx context.Context) { // слушатель
 				zap.String("main", notaryEvent.NotaryRequest.MainTransaction.Hash().String()),
 				zap.String("fb", notaryEvent.NotaryRequest.FallbackTransaction.Hash().String()))
 
-			switch notaryEvent.Type {
-			case mempoolevent.TransactionAdded:
-				scriptHash, tokenName, nftIdBytes, bet, err := s.parseNotaryEvent(notaryEvent)
-				if err != nil {
-					s.log.Error("parse notary event", zap.Error(err))
-					continue
-				}
-
-				nAct := s.notaryActor(notaryEvent.NotaryRequest.MainTransaction.Scripts[1])
-
-				var isMain bool
-				switch currentOperation {
-				case "mint":
-					isMain, err = s.checkNotaryRequestGetNft(nAct, tokenName)
-					if err != nil {
-						s.log.Error("check notary request mint", zap.Error(err))
-						continue
-					}
-				case "start":
-					isMain, err = s.checkNotaryRequestStartAuction(nAct, scriptHash, nftIdBytes, bet)
-					if err != nil {
-						s.log.Error("check notary request start", zap.Error(err))
-						continue
-					}
-				case "makeBet":
-					isMain, err = s.checkNotaryRequestMakeBet(nAct, scriptHash, bet)
-					if err != nil {
-						s.log.Error("check notary request makeBet", zap.Error(err))
-						continue
-					}
-				case "finish":
-					isMain, err = s.checkNotaryRequestFinishAuction(nAct, scriptHash)
-					if err != nil {
-						s.log.Error("check notary request finish", zap.Error(err))
-						continue
-					}
-				}
-
-				if isMain {
-					switch currentOperation {
-					case "mint":
-						err = s.proceedMainTxGetNft(ctx, nAct, notaryEvent, tokenName)
-					case "start":
-						err = s.proceedMainTxStartAuction(nAct, notaryEvent)
-					case "makeBet":
-						err = s.proceedMainTxMakeBet(nAct, notaryEvent)
-					case "finish":
-						err = s.proceedMainTxFinishAuction(nAct, notaryEvent)
-					}
-
-				} else {
-					err = s.proceedFbTx(nAct, notaryEvent)
-				}
-
-				if err != nil {
-					s.log.Error("proceed notary tx", zap.Bool("main", isMain), zap.String("token", tokenName), zap.Error(err))
-				} else {
-					s.log.Info("proceed notary tx", zap.Bool("main", isMain), zap.String("token", tokenName))
-				}
+			if notaryEvent.Type != mempoolevent.TransactionAdded {
+				continue
+			}
+
+			req, err := s.parseNotaryEvent(notaryEvent)
+			if err != nil {
+				notaryReceived.WithLabelValues("unknown").Inc()
+				s.log.Error("parse notary event", zap.Error(err))
+				continue
+			}
+			notaryReceived.WithLabelValues(req.op.method).Inc()
+
+			isNew, err := s.journal.begin(req)
+			if err != nil { // без журнала запрос может обработаться дважды, пропускаем его
+				s.log.Error("journal notary request", zap.String("hash", notaryEvent.NotaryRequest.Hash().String()), zap.Error(err))
+				continue
+			}
+			if !isNew {
+				s.log.Info("notary request is already handled", zap.String("hash", notaryEvent.NotaryRequest.Hash().String()))
+				continue
 			}
+
+			s.notaryPool.push(ctx, req) // обработка идет в воркерах, запросы одного пользователя - по порядку
 		}
 	}
 }
 
-func (s *Server) parseNotaryEvent(notaryEvent *result.NotaryRequestEvent) (util.Uint160, string, []byte, int, error) {
-	if len(notaryEvent.NotaryRequest.MainTransaction.Signers) != 3 { // подписанты:  1 - backend , который за все платит, 2 - client, который принимает на свой счет nft,
-		// 3 - нотариальный контракт сам по себе, чья подпись необходима, чтобы  нотариальный запрос состоялся
-		return util.Uint160{}, "", nil, 0, errors.New("error not enough signers")
-	}
-
-	if notaryEvent.NotaryRequest.Witness.ScriptHash().Equals(s.acc.ScriptHash()) {
-		return util.Uint160{}, "", nil, 0, fmt.Errorf("ignore owned notary request: %s", notaryEvent.NotaryRequest.Hash().String())
+// handleNotaryRequest co-signs either the main or the fallback transaction of the request.
+func (s *Server) handleNotaryRequest(ctx context.Context, req *notaryRequest) (bool, error) {
+	nAct, err := s.notaryActor(req.event.NotaryRequest.MainTransaction.Scripts[1])
+	if err != nil {
+		return false, err
 	}
+	policy := s.policy.Load()
 
-	scriptHash, tokenName, nftIdBytes, initBet, err := validateNotaryRequest(notaryEvent.NotaryRequest, s)
+	isMain, err := s.applySponsorPolicy(policy, req, policy.checkUser(req.user))
 	if err != nil {
-		return util.Uint160{}, "", nil, 0, err
+		return false, err
 	}
 
-	return scriptHash, tokenName, nftIdBytes, initBet, err
-}
-
-func validateNotaryRequest(req *payload.P2PNotaryRequest, s *Server) (util.Uint160, string, []byte, int, error) {
-	var (
-		opCode opcode.Opcode // мб = PUSH, CALL, RET и тп
-		param  []byte        // параметры инструкции
-	)
-
-	ctx := vm.NewContext(req.MainTransaction.Script) // контекст vm, будем пошагаво разбирать байт код
-	ops := make([]Op, 0, 20)                         // 20 is maximum num of opcodes for calling contracts with 4 args(no arrays of arrays)
-
-	var err error
-	for {
-		opCode, param, err = ctx.Next()
+	if isMain {
+		isMain, err = req.op.check(s, nAct, req)
 		if err != nil {
-			return util.Uint160{}, "", nil, 0, fmt.Errorf("could not get next opcode in script: %w", err)
+			return false, fmt.Errorf("check notary request: %w", err)
 		}
+	}
 
-		if opCode == opcode.RET {
-			break
+	var reservedAt time.Time
+	if isMain { // лимиты проверяем последними, чтобы не тратить бюджет на падающие tx
+		rule := policy.checkFees(req)
+		if rule == "" {
+			reservedAt = time.Now()
+			rule = s.usage.reserve(policy, req, reservedAt)
+		}
+		if isMain, err = s.applySponsorPolicy(policy, req, rule); err != nil {
+			return false, err
 		}
+	}
 
-		ops = append(ops, Op{code: opCode, param: param})
+	if isMain {
+		err = req.op.proceed(s, ctx, nAct, req)
+		if s.mainNotAccepted(req, err) { // бюджет тратит только основная tx, попавшая в блок
+			s.usage.release(req, reservedAt)
+		}
+		return true, err
 	}
+	return false, s.proceedFbTx(ctx, nAct, req.event)
+}
 
-	opsLen := len(ops)
+// mainNotAccepted reports whether the main transaction of the request can't be paid
+// by backend anymore: fallback is accepted instead or both are expired. It's false
+// while the main transaction still can be accepted, e.g. if waiting was interrupted.
+func (s *Server) mainNotAccepted(r *notaryRequest, err error) bool {
+	if errors.Is(err, waiter.ErrTxNotAccepted) { // vub прошел, ни одна из tx не принята
+		return true
+	}
 
-	contractMethod := string(ops[opsLen-3].param) // название метода - 3я с конца инструкция
-	currentOperation = contractMethod
-	var (
-		sh         util.Uint160
-		tokenName  string
-		nftIdBytes []byte
-		bet        int
-	)
+	nr := r.event.NotaryRequest
+	if _, logErr := s.rpcCli.GetApplicationLog(nr.MainTransaction.Hash(), nil); logErr == nil {
+		return false
+	}
+	_, logErr := s.rpcCli.GetApplicationLog(nr.FallbackTransaction.Hash(), nil)
+	return logErr == nil
+}
 
-	switch contractMethod {
-	case "mint":
-		sh, tokenName, err = validateNotaryRequestGetNft(req, s)
-	case "start":
-		sh, nftIdBytes, bet, err = validateNotaryRequestStartAuction(req, s)
-	case "makeBet":
-		sh, bet, err = validateNotaryRequestMakeBet(req, s)
-	case "finish":
-		err = validateNotaryRequestFinishAuction(req, s)
-	default:
-		fmt.Printf("Unknown contractMethod: %s\n", contractMethod)
+func (s *Server) parseNotaryEvent(notaryEvent *result.NotaryRequestEvent) (*notaryRequest, error) {
+	if len(notaryEvent.NotaryRequest.MainTransaction.Signers) != 3 { // подписанты:  1 - backend , который за все платит, 2 - client, который принимает на свой счет nft,
+		// 3 - нотариальный контракт сам по себе, чья подпись необходима, чтобы  нотариальный запрос состоялся
+		return nil, errors.New("error not enough signers")
+	}
+
+	if notaryEvent.NotaryRequest.Witness.ScriptHash().Equals(s.acc.ScriptHash()) {
+		return nil, fmt.Errorf("ignore owned notary request: %s", notaryEvent.NotaryRequest.Hash().String())
 	}
 
-	return sh, tokenName, nftIdBytes, bet, err
+	return s.parseNotaryRequest(notaryEvent)
 }
 
-func validateNotaryRequestPreProcessing(req *payload.P2PNotaryRequest) ([]Op, util.Uint160, error) {
+// validateNotaryRequestPreProcessing parses the contract call of the main transaction,
+// it returns call arguments, called contract and method.
+func validateNotaryRequestPreProcessing(req *payload.P2PNotaryRequest) ([]Op, util.Uint160, string, error) {
 	var (
 		opCode opcode.Opcode
 		param  []byte
@@ -482,7 +454,7 @@ func validateNotaryRequestPreProcessing(req *payload.P2PNotaryRequest) ([]Op, ut
 	for {
 		opCode, param, err = ctx.Next()
 		if err != nil {
-			return nil, util.Uint160{}, fmt.Errorf("could not get next opcode in script: %w", err)
+			return nil, util.Uint160{}, "", fmt.Errorf("could not get next opcode in script: %w", err)
 		}
 
 		if opCode == opcode.RET {
@@ -493,24 +465,29 @@ func validateNotaryRequestPreProcessing(req *payload.P2PNotaryRequest) ([]Op, ut
 	}
 
 	opsLen := len(ops)
+	if opsLen < 4 { // флаг, метод, контракт и syscall
+		return nil, util.Uint160{}, "", fmt.Errorf("too short script: %d opcodes", opsLen)
+	}
 
 	contractSysCall := make([]byte, 4)
 	binary.LittleEndian.PutUint32(contractSysCall, interopnames.ToID([]byte(interopnames.SystemContractCall)))
 	// check if it is tx with contract call
 	if !bytes.Equal(ops[opsLen-1].param, contractSysCall) {
-		return nil, util.Uint160{}, errors.New("not contract syscall")
+		return nil, util.Uint160{}, "", errors.New("not contract syscall")
 	}
 
 	// retrieve contract's script hash
 	contractHash, err := util.Uint160DecodeBytesBE(ops[opsLen-2].param) // вызываемый контракт - 2ая с конца инструкция
 	if err != nil {
-		return nil, util.Uint160{}, err
+		return nil, util.Uint160{}, "", err
 	}
 
+	contractMethod := string(ops[opsLen-3].param) // название метода - 3я с конца инструкция
+
 	// check if there is a call flag(must be in range [0:15))
 	callFlag := callflag.CallFlag(ops[opsLen-4].code - opcode.PUSH0)
 	if callFlag > callflag.All {
-		return nil, util.Uint160{}, fmt.Errorf("incorrect call flag: %s", callFlag)
+		return nil, util.Uint160{}, "", fmt.Errorf("incorrect call flag: %s", callFlag)
 	}
 
 	args := ops[:opsLen-4]
@@ -518,23 +495,24 @@ func validateNotaryRequestPreProcessing(req *payload.P2PNotaryRequest) ([]Op, ut
 	if len(args) != 0 {
 		err = validateParameterOpcodes(args)
 		if err != nil {
-			return nil, util.Uint160{}, fmt.Errorf("could not validate arguments: %w", err)
+			return nil, util.Uint160{}, "", fmt.Errorf("could not validate arguments: %w", err)
 		}
 
 		// without args packing opcodes
 		args = args[:len(args)-2]
 	}
 
-	return args, contractHash, err
+	return args, contractHash, contractMethod, err
 }
 
-func (s *Server) proceedFbTx(nAct *notary.Actor, notaryEvent *result.NotaryRequestEvent) error {
+func (s *Server) proceedFbTx(ctx context.Context, nAct *notary.Actor, notaryEvent *result.NotaryRequestEvent) error {
 	err := nAct.Sign(notaryEvent.NotaryRequest.FallbackTransaction)
 	if err != nil {
 		return fmt.Errorf("sign: %w", err)
 	}
 
-	_, err = nAct.Wait(nAct.Notarize(notaryEvent.NotaryRequest.FallbackTransaction, nil))
+	mainHash, fallbackHash, vub, err := nAct.Notarize(notaryEvent.NotaryRequest.FallbackTransaction, nil)
+	_, err = s.waitNotarized(ctx, nAct, notaryEvent.NotaryRequest, mainHash, fallbackHash, vub, err)
 	if err != nil {
 		return fmt.Errorf("wait: %w", err)
 	}
@@ -542,6 +520,32 @@ func (s *Server) proceedFbTx(nAct *notary.Actor, notaryEvent *result.NotaryReque
 	return nil
 }
 
+// waitNotarized works like notary.Actor.Wait, but stops waiting when ctx is done.
+// It journals the sent transactions and accounts which of them was accepted.
+func (s *Server) waitNotarized(ctx context.Context, nAct *notary.Actor, nr *payload.P2PNotaryRequest, mainHash, fbHash util.Uint256, vub uint32, err error) (*state.AppExecResult, error) {
+	// запрос мог уже попасть в пул или в блок, это не ошибка, его можно дождаться
+	if err != nil && !strings.Contains(strings.ToLower(err.Error()), "already exists") &&
+		!strings.Contains(strings.ToLower(err.Error()), "already on chain") {
+		return nil, rpcErr("sendrawtransaction", err)
+	}
+
+	s.journalStep(nr.Hash(), func(rec *journalRecord) {
+		rec.Step, rec.Sent, rec.VUB = stepNotarized, []util.Uint256{mainHash, fbHash}, vub
+	})
+
+	res, err := nAct.WaitAny(ctx, vub, mainHash, fbHash)
+	if err != nil {
+		return nil, err
+	}
+
+	outcome := recordAccepted(nr.MainTransaction, res)
+	s.journalStep(nr.Hash(), func(rec *journalRecord) {
+		rec.Step, rec.Outcome = stepAccepted, outcome
+	})
+
+	return res, nil
+}
+
 func parseMap(m *stackitem.Map) (map[string]string, error) {
 	items := m.Value().([]stackitem.MapElement)
 	res := make(map[string]string)
@@ -588,13 +592,15 @@ func createPool(ctx context.Context, acc *wallet.Account, addr string) (*pool.Po
 	return p, nil
 }
 
-func (s *Server) notaryActor(userWitness transaction.Witness) *notary.Actor {
+func (s *Server) notaryActor(userWitness transaction.Witness) (*notary.Actor, error) {
 	pubBytes, ok := vm.ParseSignatureContract(userWitness.VerificationScript)
 	if !ok {
-		die(errors.New("invalid verification script"))
+		return nil, errors.New("invalid verification script")
 	}
 	pub, err := keys.NewPublicKeyFromBytes(pubBytes, elliptic.P256())
-	die(err)
+	if err != nil {
+		return nil, fmt.Errorf("user public key: %w", err)
+	}
 	userAcc := notary.FakeSimpleAccount(pub)
 
 	coSigners := []actor.SignerAccount{ // симметрично clientу
@@ -615,15 +621,24 @@ func (s *Server) notaryActor(userWitness transaction.Witness) *notary.Actor {
 	}
 
 	nAct, err := notary.NewActor(s.rpcCli, coSigners, s.acc)
-	die(err)
+	if err != nil {
+		return nil, fmt.Errorf("notary actor: %w", err)
+	}
 
-	return nAct
+	return nAct, nil
 }
 
 func (s *Server) notaryDeposit(to util.Uint160) error { // на указанный адрес отправляем газ
 	data := []any{to, int64(math.MaxUint32)}
-	_, err := s.act.Wait(s.gasAct.Transfer(s.act.Sender(), notary.Hash, big.NewInt(1*native.GASFactor), data))
-	return err
+	tx, err := s.gasAct.TransferTransaction(s.act.Sender(), notary.Hash, big.NewInt(1*native.GASFactor), data)
+	if err != nil {
+		return err
+	}
+	if _, err = s.act.Wait(s.act.Send(tx)); err != nil {
+		return err
+	}
+	gasSpent.WithLabelValues("deposit").Add(1 + txFee(tx)) // сам депозит и комиссии tx пополнения
+	return nil
 }
 
 // Op is wrapper over Neo VM's opcode
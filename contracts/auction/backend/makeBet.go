@@ -1,17 +1,25 @@
 package main
 
 import (
-	"encoding/binary"
+	"context"
 	"fmt"
 
-	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
-	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/notary"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"go.uber.org/zap"
 )
 
-func (s *Server) proceedMainTxMakeBet(nAct *notary.Actor, notaryEvent *result.NotaryRequestEvent) error {
+var makeBetOperation = &operation{
+	contract: auctionContract,
+	method:   "makeBet",
+	validate: validateNotaryRequestMakeBet,
+	check:    (*Server).checkNotaryRequestMakeBet,
+	proceed:  (*Server).proceedMainTxMakeBet,
+}
+
+func (s *Server) proceedMainTxMakeBet(ctx context.Context, nAct *notary.Actor, r *notaryRequest) error {
+	notaryEvent := r.event
 
 	err := nAct.Sign(notaryEvent.NotaryRequest.MainTransaction) // sign transaction
 	if err != nil {
@@ -20,17 +28,13 @@ func (s *Server) proceedMainTxMakeBet(nAct *notary.Actor, notaryEvent *result.No
 
 	// send notary query
 	mainHash, fallbackHash, vub, err := nAct.Notarize(notaryEvent.NotaryRequest.MainTransaction, nil)
-	if err != nil {
-		return fmt.Errorf("notarize: %w", err)
-	}
-
 	s.log.Info("notarize sending",
 		zap.String("hash", notaryEvent.NotaryRequest.MainTransaction.Hash().String()),
 		zap.String("main", mainHash.String()),
 		zap.String("fallback", fallbackHash.String()),
 		zap.Uint32("vub", vub))
 
-	_, err = nAct.Wait(mainHash, fallbackHash, vub, err)
+	_, err = s.waitNotarized(ctx, nAct, notaryEvent.NotaryRequest, mainHash, fallbackHash, vub, err)
 	if err != nil {
 		return fmt.Errorf("wait: %w", err)
 	}
@@ -38,31 +42,39 @@ func (s *Server) proceedMainTxMakeBet(nAct *notary.Actor, notaryEvent *result.No
 	return nil
 }
 
-func validateNotaryRequestMakeBet(req *payload.P2PNotaryRequest, s *Server) (util.Uint160, int, error) {
+func validateNotaryRequestMakeBet(r *notaryRequest) error {
+	if len(r.args) != 2 {
+		return fmt.Errorf("invalid param length: %d", len(r.args))
+	}
 
-	args, contractHash, err := validateNotaryRequestPreProcessing(req)
+	scriptHash, err := util.Uint160DecodeBytesBE(r.args[1].Param())
 	if err != nil {
-		return util.Uint160{}, 0, err
+		return fmt.Errorf("could not decode script hash: %w", err)
 	}
 
-	bet := int(binary.LittleEndian.Uint16(args[0].Param()))
-
-	if len(args) != 2 {
-		return util.Uint160{}, 0, fmt.Errorf("invalid param length: %d", len(args))
+	bet, err := IntFromOpcode(r.args[0])
+	if err != nil {
+		return fmt.Errorf("could not parse bet: %w", err)
 	}
 
-	if !contractHash.Equals(s.auctionHash) {
-		return util.Uint160{}, 0, fmt.Errorf("unexpected contract hash: %s", contractHash)
-	}
+	r.user = scriptHash
+	r.bet = int(bet)
+	return nil
+}
 
-	scriptHash, err := util.Uint160DecodeBytesBE(args[1].Param())
+func (s *Server) checkNotaryRequestMakeBet(_ *notary.Actor, r *notaryRequest) (bool, error) {
+	res, reason, err := s.dryRun(r)
 	if err != nil {
-		return util.Uint160{}, 0, fmt.Errorf("could not decode script hash: %w", err)
+		return false, err
+	}
+	if reason != "" {
+		return s.reject(r, reason)
 	}
 
-	return scriptHash, bet, err
-}
+	msg := fmt.Sprintf("New bet = %d is made by user %s", r.bet, address.Uint160ToString(r.user))
+	if !hasInfo(res, r.contract, msg) {
+		return s.reject(r, "no bet notification")
+	}
 
-func (s *Server) checkNotaryRequestMakeBet(nAct *notary.Actor, better util.Uint160, bet int) (bool, error) {
 	return true, nil
 }
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	notaryReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "auction_backend",
+		Subsystem: "notary",
+		Name:      "received_total",
+		Help:      "Number of notary requests received from the mempool by method, unknown ones are counted as \"unknown\".",
+	}, []string{"method"})
+
+	notaryAccepted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "auction_backend",
+		Subsystem: "notary",
+		Name:      "accepted_total",
+		Help:      "Number of co-signed notary requests by the transaction accepted to the chain (main or fallback).",
+	}, []string{"tx"})
+
+	gasSpent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "auction_backend",
+		Name:      "gas_spent_total",
+		Help:      "GAS spent by the backend wallet on sponsored main transactions and notary deposits including top-up fees.",
+	}, []string{"purpose"})
+
+	depositTopUps = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "auction_backend",
+		Subsystem: "notary",
+		Name:      "deposit_topups_total",
+		Help:      "Number of notary deposit top-ups by result.",
+	}, []string{"result"})
+
+	frostfsPutDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "auction_backend",
+		Subsystem: "frostfs",
+		Name:      "put_duration_seconds",
+		Help:      "Time of putting ticket metadata to FrostFS.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2, 5, 10, 30},
+	})
+
+	frostfsPutFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "auction_backend",
+		Subsystem: "frostfs",
+		Name:      "put_failures_total",
+		Help:      "Number of failed puts of ticket metadata to FrostFS.",
+	})
+
+	rpcErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "auction_backend",
+		Subsystem: "rpc",
+		Name:      "errors_total",
+		Help:      "Number of failed RPC calls to the chain node by RPC method.",
+	}, []string{"method"})
+)
+
+// rpcErr counts the error of the RPC call, if any, and returns it as is.
+func rpcErr(method string, err error) error {
+	if err != nil {
+		rpcErrors.WithLabelValues(method).Inc()
+	}
+	return err
+}
+
+// recordAccepted counts the transaction of the notary request accepted to the chain
+// and returns the outcome of the request, backend pays only for the main one,
+// fallback is paid from the user deposit.
+func recordAccepted(mainTx *transaction.Transaction, res *state.AppExecResult) string {
+	outcome := outcomeFallback
+	if res.Container.Equals(mainTx.Hash()) {
+		outcome = outcomeMain
+		gasSpent.WithLabelValues("main").Add(txFee(mainTx))
+	}
+	notaryAccepted.WithLabelValues(outcome).Inc()
+	return outcome
+}
+
+// txFee returns the total fee of the transaction in GAS.
+func txFee(tx *transaction.Transaction) float64 {
+	return fixedn.Fixed8(tx.SystemFee + tx.NetworkFee).FloatValue()
+}
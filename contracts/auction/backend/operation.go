@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/invoker"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/notary"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+	"go.uber.org/zap"
+)
+
+// contractKind is a contract of the dApp, hashes of the contracts may change
+// after redeploy, so operations are bound to the kind rather than to the hash.
+type contractKind int
+
+const (
+	unknownContract contractKind = iota
+	nftContract
+	auctionContract
+)
+
+// operation describes how the backend handles notary requests invoking one contract method.
+type operation struct {
+	contract contractKind
+	method   string
+	// validate parses arguments of the main transaction into the request.
+	validate func(r *notaryRequest) error
+	// check decides whether the main transaction should be co-signed, otherwise fallback is signed.
+	check func(s *Server, nAct *notary.Actor, r *notaryRequest) (bool, error)
+	// proceed co-signs and sends the main transaction.
+	proceed func(s *Server, ctx context.Context, nAct *notary.Actor, r *notaryRequest) error
+}
+
+type operationKey struct {
+	contract contractKind
+	method   string
+}
+
+// operationRegistry holds handlers of all supported contract methods.
+type operationRegistry map[operationKey]*operation
+
+// notaryRequest is a parsed notary request together with the operation it invokes.
+type notaryRequest struct {
+	event    *result.NotaryRequestEvent
+	op       *operation
+	contract util.Uint160
+	args     []Op // аргументы вызова в обратном порядке
+
+	user      util.Uint160 // пользователь, от имени которого вызывается метод
+	tokenName string
+	domain    string // имя поддомена аукциона, регистрируемого при старте
+	nftID     []byte
+	bet       int
+}
+
+func newOperationRegistry(ops ...*operation) operationRegistry {
+	r := make(operationRegistry, len(ops))
+	for _, op := range ops {
+		r[operationKey{contract: op.contract, method: op.method}] = op
+	}
+	return r
+}
+
+func (r operationRegistry) get(contract contractKind, method string) (*operation, bool) {
+	op, ok := r[operationKey{contract: contract, method: method}]
+	return op, ok
+}
+
+// contractKind returns which contract of the dApp the hash belongs to.
+func (s *Server) contractKind(h util.Uint160) contractKind {
+	hashes := s.hashes.Load()
+	switch {
+	case h.Equals(hashes.nft):
+		return nftContract
+	case h.Equals(hashes.auction):
+		return auctionContract
+	default:
+		return unknownContract
+	}
+}
+
+// parseNotaryRequest finds operation of the main transaction and validates its arguments.
+func (s *Server) parseNotaryRequest(event *result.NotaryRequestEvent) (*notaryRequest, error) {
+	args, contractHash, method, err := validateNotaryRequestPreProcessing(event.NotaryRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	op, ok := s.ops.get(s.contractKind(contractHash), method)
+	if !ok {
+		return nil, fmt.Errorf("unsupported method %s of contract %s", method, contractHash.StringLE())
+	}
+
+	r := &notaryRequest{
+		event:    event,
+		op:       op,
+		contract: contractHash,
+		args:     args,
+	}
+	if err = op.validate(r); err != nil {
+		return nil, fmt.Errorf("validate %s: %w", method, err)
+	}
+
+	return r, nil
+}
+
+// dryRun test-invokes the main transaction with its signers. Empty reason means
+// the transaction is going to succeed, otherwise the fallback has to be signed.
+func (s *Server) dryRun(r *notaryRequest) (*result.Invoke, string, error) {
+	tx := r.event.NotaryRequest.MainTransaction
+
+	// контракты не проверяют подпись пользователя из аргументов, поэтому это делает backend
+	if !r.user.Equals(tx.Signers[1].Account) {
+		return nil, "user argument is not the request signer", nil
+	}
+
+	res, err := invoker.New(s.rpcCli, tx.Signers).Run(tx.Script)
+	if err = rpcErr("invokescript", err); err != nil {
+		return nil, "", fmt.Errorf("invoke script: %w", err)
+	}
+	if res.State != vmstate.Halt.String() {
+		return nil, "main tx faults: " + res.FaultException, nil
+	}
+	if len(res.Stack) != 1 { // скрипт - вызов одного метода, результат всегда один (Null для void)
+		return nil, fmt.Sprintf("unexpected result stack length: %d", len(res.Stack)), nil
+	}
+
+	return res, "", nil
+}
+
+// reject logs why the main transaction isn't co-signed.
+func (s *Server) reject(r *notaryRequest, reason string) (bool, error) {
+	s.log.Info("reject main tx, fallback will be signed",
+		zap.String("hash", r.event.NotaryRequest.Hash().String()),
+		zap.String("method", r.op.method), zap.String("reason", reason))
+	return false, nil
+}
+
+// findNotification returns arguments of the first event with the given name
+// emitted by the contract or nil if there is none.
+func findNotification(res *result.Invoke, contract util.Uint160, name string) []stackitem.Item {
+	for _, ev := range res.Notifications {
+		if ev.ScriptHash.Equals(contract) && ev.Name == name {
+			return ev.Item.Value().([]stackitem.Item)
+		}
+	}
+	return nil
+}
+
+// hasInfo checks that the auction contract emitted info event with the message.
+func hasInfo(res *result.Invoke, contract util.Uint160, msg string) bool {
+	for _, ev := range res.Notifications {
+		if !ev.ScriptHash.Equals(contract) || ev.Name != "info" {
+			continue
+		}
+		args := ev.Item.Value().([]stackitem.Item)
+		if len(args) == 1 {
+			if data, err := args[0].TryBytes(); err == nil && string(data) == msg {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const (
+	cfgSponsorship = "sponsorship"
+
+	violationFallback = "fallback" // подписать fallback транзакцию вместо основной
+	violationReject   = "reject"   // не подписывать ничего
+
+	// methodStartNamed is the policy operation of the auction start registering
+	// the auction subdomain, it burns the NNS registration price.
+	methodStartNamed = "startNamed"
+)
+
+// errPolicyRejected means the request violates sponsorship policy and is ignored.
+var errPolicyRejected = errors.New("rejected by sponsorship policy")
+
+var (
+	sponsorshipViolations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "auction_backend",
+		Subsystem: "sponsorship",
+		Name:      "violations_total",
+		Help:      "Number of notary requests violating sponsorship policy by method and rule.",
+	}, []string{"method", "rule"})
+
+	sponsorshipDailySpent = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "auction_backend",
+		Subsystem: "sponsorship",
+		Name:      "daily_spent_gas",
+		Help:      "GAS spent on sponsored main transactions since the start of the day (UTC).",
+	})
+)
+
+// sponsorshipConfig is the sponsorship section of the config.
+type sponsorshipConfig struct {
+	DailyGasBudget float64                          `mapstructure:"daily_gas_budget"`
+	Allow          []string                         `mapstructure:"allow"`
+	Deny           []string                         `mapstructure:"deny"`
+	Operations     map[string]operationPolicyConfig `mapstructure:"operations"`
+}
+
+type operationPolicyConfig struct {
+	MaxSystemFee   float64 `mapstructure:"max_system_fee"`
+	MaxNetworkFee  float64 `mapstructure:"max_network_fee"`
+	MaxPerUserHour int     `mapstructure:"max_per_user_hour"`
+	MaxPerUserDay  int     `mapstructure:"max_per_user_day"`
+	OnViolation    string  `mapstructure:"on_violation"`
+}
+
+// sponsorPolicy is a parsed sponsorship policy, zero limits mean no limit.
+// It's never modified after creation, reload swaps the whole policy.
+type sponsorPolicy struct {
+	dailyBudget int64
+	allow       map[util.Uint160]struct{} // пустой список - разрешены все
+	deny        map[util.Uint160]struct{}
+	operations  map[string]operationPolicy
+}
+
+type operationPolicy struct {
+	maxSystemFee   int64
+	maxNetworkFee  int64
+	maxPerUserHour int
+	maxPerUserDay  int
+	onViolation    string
+}
+
+// sponsorUsage counts sponsored transactions, it survives policy reloads.
+type sponsorUsage struct {
+	mu       sync.Mutex
+	day      time.Time
+	spent    int64
+	userTxes map[userMethod][]time.Time // время спонсированных tx пользователя за последние сутки
+}
+
+type userMethod struct {
+	user   util.Uint160
+	method string
+}
+
+func loadSponsorPolicy(v *viper.Viper) (*sponsorPolicy, error) {
+	var cfg sponsorshipConfig
+	if err := v.UnmarshalKey(cfgSponsorship, &cfg); err != nil {
+		return nil, fmt.Errorf("sponsorship config: %w", err)
+	}
+
+	p := &sponsorPolicy{
+		dailyBudget: int64(fixedn.Fixed8FromFloat(cfg.DailyGasBudget)),
+		operations:  make(map[string]operationPolicy, len(cfg.Operations)),
+	}
+
+	var err error
+	if p.allow, err = parseAddressSet(cfg.Allow); err != nil {
+		return nil, fmt.Errorf("sponsorship allow list: %w", err)
+	}
+	if p.deny, err = parseAddressSet(cfg.Deny); err != nil {
+		return nil, fmt.Errorf("sponsorship deny list: %w", err)
+	}
+
+	for method, opCfg := range cfg.Operations {
+		op := operationPolicy{
+			maxSystemFee:   int64(fixedn.Fixed8FromFloat(opCfg.MaxSystemFee)),
+			maxNetworkFee:  int64(fixedn.Fixed8FromFloat(opCfg.MaxNetworkFee)),
+			maxPerUserHour: opCfg.MaxPerUserHour,
+			maxPerUserDay:  opCfg.MaxPerUserDay,
+			onViolation:    opCfg.OnViolation,
+		}
+		switch op.onViolation {
+		case "":
+			op.onViolation = violationFallback
+		case violationFallback, violationReject:
+		default:
+			return nil, fmt.Errorf("operation %s: unknown on_violation: %s", method, op.onViolation)
+		}
+		p.operations[strings.ToLower(method)] = op // viper приводит ключи к нижнему регистру
+	}
+
+	return p, nil
+}
+
+func parseAddressSet(addrs []string) (map[util.Uint160]struct{}, error) {
+	res := make(map[util.Uint160]struct{}, len(addrs))
+	for _, addr := range addrs {
+		sh, err := address.StringToUint160(addr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", addr, err)
+		}
+		res[sh] = struct{}{}
+	}
+	return res, nil
+}
+
+// policyMethod returns the policy operation of the request, it's the called
+// method except for the named auction start.
+func policyMethod(r *notaryRequest) string {
+	if r.op.method == "start" && r.domain != "" {
+		return methodStartNamed
+	}
+	return r.op.method
+}
+
+func (p *sponsorPolicy) operation(method string) operationPolicy {
+	if op, ok := p.operations[strings.ToLower(method)]; ok {
+		return op
+	}
+	return operationPolicy{onViolation: violationFallback}
+}
+
+// checkUser returns the violated rule if the user can't be sponsored at all.
+func (p *sponsorPolicy) checkUser(user util.Uint160) string {
+	if _, ok := p.deny[user]; ok {
+		return "deny_list"
+	}
+	if _, ok := p.allow[user]; len(p.allow) != 0 && !ok {
+		return "allow_list"
+	}
+	return ""
+}
+
+// checkFees returns the violated rule if the main transaction is too expensive.
+func (p *sponsorPolicy) checkFees(r *notaryRequest) string {
+	op := p.operation(policyMethod(r))
+	tx := r.event.NotaryRequest.MainTransaction
+
+	if op.maxSystemFee > 0 && tx.SystemFee > op.maxSystemFee {
+		return "max_system_fee"
+	}
+	if op.maxNetworkFee > 0 && tx.NetworkFee > op.maxNetworkFee {
+		return "max_network_fee"
+	}
+	return ""
+}
+
+// reserve accounts the main transaction of the request if it fits user rate
+// and daily budget limits, otherwise it returns the violated rule. The reservation
+// is released if the main transaction isn't accepted.
+func (u *sponsorUsage) reserve(p *sponsorPolicy, r *notaryRequest, now time.Time) string {
+	op := p.operation(policyMethod(r))
+	tx := r.event.NotaryRequest.MainTransaction
+	fee := tx.SystemFee + tx.NetworkFee
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if day := now.UTC().Truncate(24 * time.Hour); !day.Equal(u.day) { // новые сутки - бюджет заново
+		u.day = day
+		u.spent = 0
+	}
+
+	if u.userTxes == nil {
+		u.userTxes = make(map[userMethod][]time.Time)
+	}
+	key := userMethod{user: r.user, method: policyMethod(r)}
+	txes := u.userTxes[key]
+	for len(txes) != 0 && now.Sub(txes[0]) >= 24*time.Hour {
+		txes = txes[1:]
+	}
+	if len(txes) == 0 {
+		delete(u.userTxes, key)
+	}
+
+	lastHour := 0
+	for _, t := range txes {
+		if now.Sub(t) < time.Hour {
+			lastHour++
+		}
+	}
+	if op.maxPerUserHour > 0 && lastHour >= op.maxPerUserHour {
+		return "max_per_user_hour"
+	}
+	if op.maxPerUserDay > 0 && len(txes) >= op.maxPerUserDay {
+		return "max_per_user_day"
+	}
+	if p.dailyBudget > 0 && u.spent+fee > p.dailyBudget {
+		return "daily_gas_budget"
+	}
+
+	u.spent += fee
+	u.userTxes[key] = append(txes, now)
+	sponsorshipDailySpent.Set(fixedn.Fixed8(u.spent).FloatValue())
+	return ""
+}
+
+// release cancels the reservation made at reservedAt, the main transaction isn't
+// accepted and the backend hasn't paid for it.
+func (u *sponsorUsage) release(r *notaryRequest, reservedAt time.Time) {
+	tx := r.event.NotaryRequest.MainTransaction
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if reservedAt.UTC().Truncate(24 * time.Hour).Equal(u.day) { // бюджет прошлых суток уже обнулен
+		u.spent -= tx.SystemFee + tx.NetworkFee
+		sponsorshipDailySpent.Set(fixedn.Fixed8(u.spent).FloatValue())
+	}
+
+	key := userMethod{user: r.user, method: policyMethod(r)}
+	txes := u.userTxes[key]
+	for i, t := range txes {
+		if t.Equal(reservedAt) {
+			txes = append(txes[:i:i], txes[i+1:]...)
+			break
+		}
+	}
+	if len(txes) == 0 {
+		delete(u.userTxes, key)
+	} else {
+		u.userTxes[key] = txes
+	}
+}
+
+// applySponsorPolicy checks the request against the policy. It returns whether the main
+// transaction may still be sponsored or errPolicyRejected if nothing should be signed.
+func (s *Server) applySponsorPolicy(p *sponsorPolicy, r *notaryRequest, rule string) (bool, error) {
+	if rule == "" {
+		return true, nil
+	}
+
+	method := policyMethod(r)
+	sponsorshipViolations.WithLabelValues(method, rule).Inc()
+	s.log.Info("sponsorship policy violation",
+		zap.String("hash", r.event.NotaryRequest.Hash().String()), zap.String("method", method),
+		zap.String("user", address.Uint160ToString(r.user)), zap.String("rule", rule))
+
+	if p.operation(method).onViolation == violationReject {
+		return false, fmt.Errorf("%w: %s", errPolicyRejected, rule)
+	}
+	return false, nil
+}
+
+// runPolicyReloader reloads sponsorship policy from the config file on SIGHUP.
+func (s *Server) runPolicyReloader(ctx context.Context, cfgPath string) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	defer signal.Stop(ch)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ch:
+			if err := s.reloadSponsorPolicy(cfgPath); err != nil {
+				s.log.Error("reload sponsorship policy, keep the old one", zap.Error(err))
+				continue
+			}
+			s.log.Info("sponsorship policy reloaded")
+		}
+	}
+}
+
+func (s *Server) reloadSponsorPolicy(cfgPath string) error {
+	f, err := os.Open(cfgPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	v := viper.New() // глобальный viper не трогаем, его читают другие горутины
+	v.SetConfigType("yml")
+	if err = v.ReadConfig(f); err != nil {
+		return err
+	}
+
+	p, err := loadSponsorPolicy(v)
+	if err != nil {
+		return err
+	}
+	s.policy.Store(p)
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"contract/wrappers/nns"
+
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultRenewBefore        = 30 * 24 * time.Hour
+	defaultRenewCheckInterval = time.Hour
+)
+
+var (
+	domainExpiration = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "auction_backend",
+		Subsystem: "nns",
+		Name:      "domain_expiration_seconds",
+		Help:      "Expiration time of NNS domain as unix timestamp.",
+	}, []string{"domain"})
+
+	domainRenewals = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "auction_backend",
+		Subsystem: "nns",
+		Name:      "domain_renewals_total",
+		Help:      "Number of NNS domain renewal attempts by result.",
+	}, []string{"domain", "result"})
+)
+
+// runDomainRenewer renews configured NNS domains when they are about to expire,
+// nns stops resolving expired domains, so the whole dApp would break.
+func (s *Server) runDomainRenewer(ctx context.Context, domains []string, before, interval time.Duration) {
+	if len(domains) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, domain := range domains {
+			if err := s.checkDomainExpiration(domain, before); err != nil {
+				domainRenewals.WithLabelValues(domain, "failed").Inc()
+				s.log.Error("renew domain", zap.String("domain", domain), zap.Error(err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) checkDomainExpiration(domain string, before time.Duration) error {
+	c := nns.New(s.act, s.nnsHash)
+
+	expiration, err := getDomainExpiration(c, domain)
+	if err != nil {
+		return err
+	}
+	domainExpiration.WithLabelValues(domain).Set(float64(expiration.Unix()))
+
+	left := time.Until(expiration)
+	if left > before {
+		return nil
+	}
+	if left <= 0 { // nns не дает продлить уже истекший домен, его придется регистрировать заново
+		return fmt.Errorf("domain has expired at %s", expiration)
+	}
+
+	s.log.Info("renew domain", zap.String("domain", domain), zap.Time("expiration", expiration))
+
+	var (
+		h   util.Uint256
+		vub uint32
+	)
+	if domain == auctionDomain { // админ домена аукциона - сам контракт, продлить домен можно только через него
+		h, vub, err = s.act.SendCall(s.auctionHash(), "renewDomain")
+	} else { // продление оплачивает кошелек backend, он должен быть владельцем или админом домена
+		h, vub, err = c.Renew(domain)
+	}
+	res, err := s.act.Wait(h, vub, err)
+	if err != nil {
+		return fmt.Errorf("renew: %w", err)
+	}
+	if res.VMState != vmstate.Halt {
+		return fmt.Errorf("renew tx failed: %s", res.FaultException)
+	}
+
+	expiration, err = getDomainExpiration(c, domain)
+	if err != nil {
+		return err
+	}
+	domainExpiration.WithLabelValues(domain).Set(float64(expiration.Unix()))
+	domainRenewals.WithLabelValues(domain, "success").Inc()
+
+	s.log.Info("domain renewed", zap.String("domain", domain), zap.Time("expiration", expiration))
+	return nil
+}
+
+// getDomainExpiration returns expiration of the domain from its nns properties.
+func getDomainExpiration(c *nns.Contract, domain string) (time.Time, error) {
+	m, err := c.Properties([]byte(domain))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("properties: %w", err)
+	}
+
+	for _, item := range m.Value().([]stackitem.MapElement) {
+		k, err := item.Key.TryBytes()
+		if err != nil || string(k) != "expiration" {
+			continue
+		}
+
+		ms, err := item.Value.TryInteger()
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid expiration: %w", err)
+		}
+		return time.UnixMilli(ms.Int64()), nil // в nns время хранится в миллисекундах
+	}
+
+	return time.Time{}, errors.New("no expiration in properties")
+}
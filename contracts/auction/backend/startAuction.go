@@ -1,17 +1,26 @@
 package main
 
 import (
-	"encoding/binary"
+	"context"
 	"fmt"
 
-	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
-	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/notary"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"go.uber.org/zap"
 )
 
-func (s *Server) proceedMainTxStartAuction(nAct *notary.Actor, notaryEvent *result.NotaryRequestEvent) error {
+var startAuctionOperation = &operation{
+	contract: auctionContract,
+	method:   "start",
+	validate: validateNotaryRequestStartAuction,
+	check:    (*Server).checkNotaryRequestStartAuction,
+	proceed:  (*Server).proceedMainTxStartAuction,
+}
+
+func (s *Server) proceedMainTxStartAuction(ctx context.Context, nAct *notary.Actor, r *notaryRequest) error {
+	notaryEvent := r.event
+
 	err := nAct.Sign(notaryEvent.NotaryRequest.MainTransaction)
 	if err != nil {
 		return fmt.Errorf("sign: %w", err)
@@ -23,7 +32,7 @@ func (s *Server) proceedMainTxStartAuction(nAct *notary.Actor, notaryEvent *resu
 		zap.String("main", mainHash.String()), zap.String("fb", fallbackHash.String()),
 		zap.Uint32("vub", vub))
 
-	_, err = nAct.Wait(mainHash, fallbackHash, vub, err) // ждем, пока какая-нибудь tx будет принята
+	_, err = s.waitNotarized(ctx, nAct, notaryEvent.NotaryRequest, mainHash, fallbackHash, vub, err) // ждем, пока какая-нибудь tx будет принята
 	if err != nil {
 		return fmt.Errorf("wait: %w", err)
 	}
@@ -31,35 +40,41 @@ func (s *Server) proceedMainTxStartAuction(nAct *notary.Actor, notaryEvent *resu
 	return nil
 }
 
-func validateNotaryRequestStartAuction(req *payload.P2PNotaryRequest, s *Server) (util.Uint160, []byte, int, error) {
-
-	args, contractHash, err := validateNotaryRequestPreProcessing(req)
-	if err != nil {
-		return util.Uint160{}, nil, 0, err
+func validateNotaryRequestStartAuction(r *notaryRequest) error {
+	if len(r.args) != 4 { // start принимает ровно 4 аргумента, последний - имя поддомена аукциона (может быть пустым)
+		return fmt.Errorf("invalid param length: %d", len(r.args))
 	}
 
-	contractHashExpected := s.auctionHash // вызываемый контракт
-
-	if !contractHash.Equals(contractHashExpected) {
-		return util.Uint160{}, nil, 0, fmt.Errorf("unexpected contract hash: %s", contractHash)
+	sh, err := util.Uint160DecodeBytesBE(r.args[3].Param())
+	if err != nil {
+		return fmt.Errorf("could not decode script hash: %w", err)
 	}
 
-	if len(args) != 3 { // start принимает ровно 3 аргумента
-		return util.Uint160{}, nil, 0, fmt.Errorf("invalid param length: %d", len(args))
+	initBet, err := IntFromOpcode(r.args[1])
+	if err != nil {
+		return fmt.Errorf("could not parse initial bet: %w", err)
 	}
 
-	nftIdBytes := args[1].Param()
-
-	initBet := int(binary.LittleEndian.Uint16(args[0].Param()))
+	r.user = sh
+	r.nftID = r.args[2].Param()
+	r.bet = int(initBet)
+	r.domain = string(r.args[0].Param())
+	return nil
+}
 
-	sh, err := util.Uint160DecodeBytesBE(args[2].Param())
+func (s *Server) checkNotaryRequestStartAuction(_ *notary.Actor, r *notaryRequest) (bool, error) {
+	res, reason, err := s.dryRun(r)
 	if err != nil {
-		return util.Uint160{}, nil, 0, fmt.Errorf("could not decode script hash: %w", err)
+		return false, err
+	}
+	if reason != "" {
+		return s.reject(r, reason)
 	}
 
-	return sh, nftIdBytes, initBet, err
-}
+	msg := fmt.Sprintf("New auction started with initial bet = %d by user %s", r.bet, address.Uint160ToString(r.user))
+	if !hasInfo(res, r.contract, msg) {
+		return s.reject(r, "no auction start notification")
+	}
 
-func (s *Server) checkNotaryRequestStartAuction(nAct *notary.Actor, organizer util.Uint160, lotId []byte, initBet int) (bool, error) {
 	return true, nil
 }
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/unwrap"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMetadataRetries     = 5
+	defaultMetadataBackoff     = time.Second
+	maxMetadataBackoff         = 30 * time.Second
+	defaultMetadataAttempts    = 5
+	defaultReconcileInterval   = 10 * time.Minute
+	metadataUnavailableMessage = "metadata unavailable"
+
+	// что делать с билетом, метаданные которого получить не удалось
+	onMetadataFailureNone = "none" // только писать в лог
+	onMetadataFailureFlag = "flag" // пометить токен, пометка видна в properties
+	onMetadataFailureBurn = "burn" // сжечь токен
+)
+
+// errMetadataNotFound means the ticket API doesn't know the ticket, retries won't help.
+var errMetadataNotFound = errors.New("ticket metadata not found")
+
+var ticketsReconciled = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "auction_backend",
+	Subsystem: "tickets",
+	Name:      "reconciled_total",
+	Help:      "Number of reconciliation attempts of tickets without metadata address by result.",
+}, []string{"result"})
+
+// metadataPolicy describes how ticket metadata upload is retried and what is done
+// with the ticket if the metadata can't be obtained.
+type metadataPolicy struct {
+	retries     int           // попыток загрузки подряд
+	backoff     time.Duration // пауза перед второй попыткой, дальше удваивается
+	maxAttempts int           // проходов сверки, после которых применяется onFailure
+	onFailure   string
+}
+
+func newMetadataPolicy(retries int, backoff time.Duration, maxAttempts int, onFailure string) (metadataPolicy, error) {
+	if retries <= 0 {
+		retries = defaultMetadataRetries
+	}
+	if backoff <= 0 {
+		backoff = defaultMetadataBackoff
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMetadataAttempts
+	}
+	switch onFailure {
+	case "":
+		onFailure = onMetadataFailureNone
+	case onMetadataFailureNone, onMetadataFailureFlag, onMetadataFailureBurn:
+	default:
+		return metadataPolicy{}, fmt.Errorf("unknown metadata failure policy: %s", onFailure)
+	}
+
+	return metadataPolicy{
+		retries:     retries,
+		backoff:     backoff,
+		maxAttempts: maxAttempts,
+		onFailure:   onFailure,
+	}, nil
+}
+
+// putTicketMetaRetry puts ticket metadata to FrostFS retrying with exponential backoff.
+func (s *Server) putTicketMetaRetry(ctx context.Context, tokenName string) (string, error) {
+	backoff := s.metadata.backoff
+	for attempt := 1; ; attempt++ {
+		addr, err := s.putTicketMeta(ctx, tokenName)
+		if err == nil || errors.Is(err, errMetadataNotFound) || attempt >= s.metadata.retries {
+			return addr, err
+		}
+
+		s.log.Warn("put ticket metadata, retrying", zap.String("token", tokenName),
+			zap.Int("attempt", attempt), zap.Duration("backoff", backoff), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("%w (last error: %w)", ctx.Err(), err)
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxMetadataBackoff)
+	}
+}
+
+// invokeNft sends the transaction calling the nft method and waits for its successful execution.
+func (s *Server) invokeNft(ctx context.Context, method string, args ...any) error {
+	h, vub, err := s.act.SendCall(s.nftHash(), method, args...)
+	if err != nil {
+		return fmt.Errorf("send %s: %w", method, err)
+	}
+
+	res, err := s.act.WaitAny(ctx, vub, h)
+	if err != nil {
+		return fmt.Errorf("wait %s: %w", method, err)
+	}
+	if res.VMState != vmstate.Halt {
+		return fmt.Errorf("%s faulted: %s", method, res.FaultException)
+	}
+	return nil
+}
+
+// ticketReconciler keeps state between reconciliation passes.
+type ticketReconciler struct {
+	failures map[string]int    // неудачные проходы по имени билета
+	uploaded map[string]string // уже загруженные метаданные, адрес которых не удалось записать
+}
+
+// runTicketReconciler periodically finds tickets without metadata address and uploads
+// their metadata once again.
+func (s *Server) runTicketReconciler(ctx context.Context, interval time.Duration) {
+	r := &ticketReconciler{
+		failures: make(map[string]int),
+		uploaded: make(map[string]string),
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		if err := s.reconcileTickets(ctx, r); err != nil {
+			s.log.Error("reconcile tickets", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (s *Server) reconcileTickets(ctx context.Context, r *ticketReconciler) error {
+	ids, err := unwrap.ArrayOfBytes(s.act.Call(s.nftHash(), "tokensList"))
+	if err != nil {
+		return fmt.Errorf("tokens list: %w", err)
+	}
+	pending, err := s.journal.pendingTokens()
+	if err != nil {
+		return fmt.Errorf("journal: %w", err)
+	}
+
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		m, err := unwrap.Map(s.act.Call(s.nftHash(), "properties", id))
+		if err != nil {
+			s.log.Warn("ticket properties", zap.Binary("id", id), zap.Error(err))
+			continue
+		}
+		props, err := parseMap(m)
+		if err != nil {
+			s.log.Warn("parse ticket properties", zap.Binary("id", id), zap.Error(err))
+			continue
+		}
+
+		name := props["name"]
+		if props["address"] != "" {
+			delete(r.failures, name)
+			delete(r.uploaded, name)
+			continue
+		}
+		if _, ok := pending[name]; ok { // билет еще обрабатывает воркер
+			continue
+		}
+
+		log := s.log.With(zap.String("token", name))
+		if err = s.reconcileTicket(ctx, r, name); err == nil {
+			ticketsReconciled.WithLabelValues("uploaded").Inc()
+			log.Info("ticket metadata reconciled")
+			delete(r.failures, name)
+			delete(r.uploaded, name)
+			continue
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		ticketsReconciled.WithLabelValues("failed").Inc()
+		r.failures[name]++
+		log.Warn("reconcile ticket metadata", zap.Int("failures", r.failures[name]), zap.Error(err))
+
+		if errors.Is(err, errMetadataNotFound) || r.failures[name] >= s.metadata.maxAttempts {
+			s.applyMetadataPolicy(ctx, r, id, name, props["flag"] != "")
+		}
+	}
+
+	return nil
+}
+
+// reconcileTicket uploads metadata of the ticket, if it's not uploaded yet, and sets its address.
+func (s *Server) reconcileTicket(ctx context.Context, r *ticketReconciler, name string) error {
+	addr := r.uploaded[name]
+	if addr == "" {
+		var err error
+		if addr, err = s.journal.ticketAddress(name); err != nil {
+			return fmt.Errorf("journal: %w", err)
+		}
+	}
+	if addr == "" {
+		var err error
+		if addr, err = s.putTicketMetaRetry(ctx, name); err != nil {
+			return err
+		}
+		r.uploaded[name] = addr // при ошибке setAddress повторно не загружаем
+	}
+
+	return s.invokeNft(ctx, "setAddress", name, addr)
+}
+
+// applyMetadataPolicy flags or burns the ticket whose metadata can't be obtained.
+func (s *Server) applyMetadataPolicy(ctx context.Context, r *ticketReconciler, id []byte, name string, flagged bool) {
+	log := s.log.With(zap.String("token", name), zap.String("policy", s.metadata.onFailure))
+
+	var err error
+	switch s.metadata.onFailure {
+	case onMetadataFailureFlag:
+		if flagged {
+			return
+		}
+		if err = s.invokeNft(ctx, "flag", name, metadataUnavailableMessage); err == nil {
+			ticketsReconciled.WithLabelValues("flagged").Inc()
+		}
+	case onMetadataFailureBurn:
+		var isLot bool
+		if isLot, err = s.isAuctionLot(id); err != nil {
+			break
+		}
+		if isLot { // без лота аукцион не завершится, сожжем билет на следующем проходе после аукциона
+			log.Warn("ticket is the lot of the running auction, burning postponed")
+			return
+		}
+		if err = s.invokeNft(ctx, "burn", name); err == nil {
+			ticketsReconciled.WithLabelValues("burned").Inc()
+			delete(r.failures, name)
+			delete(r.uploaded, name)
+		}
+	default:
+		log.Error("ticket metadata can't be obtained")
+		return
+	}
+
+	if err != nil {
+		log.Error("apply metadata failure policy", zap.Error(err))
+		return
+	}
+	log.Warn("ticket metadata can't be obtained, policy applied")
+}
+
+// isAuctionLot checks whether the token is the lot of the running auction.
+func (s *Server) isAuctionLot(id []byte) (bool, error) {
+	lot, err := unwrap.Bytes(s.act.Call(s.auctionHash(), "showLotId"))
+	if err != nil {
+		return false, fmt.Errorf("auction lot: %w", err)
+	}
+	return bytes.Equal(lot, id), nil
+}
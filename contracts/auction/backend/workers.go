@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync/atomic"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultNotaryWorkers   = 4
+	defaultNotaryQueueSize = 64
+	defaultNotaryTimeout   = 2 * time.Minute
+)
+
+var (
+	notaryQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "auction_backend",
+		Subsystem: "notary",
+		Name:      "queue_depth",
+		Help:      "Number of notary requests waiting for a worker.",
+	})
+
+	notaryRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "auction_backend",
+		Subsystem: "notary",
+		Name:      "requests_total",
+		Help:      "Number of processed notary requests by method and result.",
+	}, []string{"method", "result"})
+
+	notaryRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "auction_backend",
+		Subsystem: "notary",
+		Name:      "request_duration_seconds",
+		Help:      "Time of notary request processing from taking by a worker till the tx acceptance.",
+		Buckets:   []float64{0.5, 1, 2, 5, 10, 20, 30, 60, 120},
+	}, []string{"method"})
+)
+
+// notaryPool processes notary requests concurrently. Requests of the same user
+// always go to the same worker, so they are handled in the order they came in.
+type notaryPool struct {
+	s       *Server
+	queues  []chan *notaryRequest
+	timeout time.Duration
+	depth   atomic.Int64
+}
+
+func newNotaryPool(s *Server, workers, queueSize int, timeout time.Duration) *notaryPool {
+	if workers <= 0 {
+		workers = defaultNotaryWorkers
+	}
+	if queueSize <= 0 {
+		queueSize = defaultNotaryQueueSize
+	}
+	if timeout <= 0 {
+		timeout = defaultNotaryTimeout
+	}
+
+	p := &notaryPool{
+		s:       s,
+		queues:  make([]chan *notaryRequest, workers),
+		timeout: timeout,
+	}
+	for i := range p.queues {
+		p.queues[i] = make(chan *notaryRequest, queueSize)
+	}
+
+	return p
+}
+
+// start runs workers until ctx is done.
+func (p *notaryPool) start(ctx context.Context) {
+	for i, q := range p.queues {
+		go p.worker(ctx, i, q)
+	}
+}
+
+// push puts request to the queue of its user's worker, it blocks while the queue
+// is full, so slow processing slows down reading of new requests too.
+func (p *notaryPool) push(ctx context.Context, r *notaryRequest) {
+	i := p.shard(r.user)
+	depth := p.depth.Add(1)
+	notaryQueueDepth.Set(float64(depth))
+
+	log := p.s.log.With(zap.String("hash", r.event.NotaryRequest.Hash().String()),
+		zap.String("method", r.op.method), zap.Int("worker", i), zap.Int64("queue_depth", depth))
+	if len(p.queues[i]) == cap(p.queues[i]) {
+		log.Warn("notary worker queue is full")
+	}
+
+	select {
+	case p.queues[i] <- r:
+		log.Debug("notary request queued")
+	case <-ctx.Done():
+		notaryQueueDepth.Set(float64(p.depth.Add(-1)))
+	}
+}
+
+// shard returns index of the worker for the user.
+func (p *notaryPool) shard(user util.Uint160) int {
+	h := fnv.New32a()
+	_, _ = h.Write(user.BytesBE())
+	return int(h.Sum32() % uint32(len(p.queues)))
+}
+
+func (p *notaryPool) worker(ctx context.Context, i int, q <-chan *notaryRequest) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case r := <-q:
+			notaryQueueDepth.Set(float64(p.depth.Add(-1)))
+			p.process(ctx, i, r)
+		}
+	}
+}
+
+func (p *notaryPool) process(parent context.Context, i int, r *notaryRequest) {
+	ctx, cancel := context.WithTimeout(parent, p.timeout) // ожидание tx не должно держать воркер бесконечно
+	defer cancel()
+
+	start := time.Now()
+	isMain, err := p.s.handleNotaryRequest(ctx, r)
+	if parent.Err() != nil { // backend останавливается, запрос будет продолжен после перезапуска
+		return
+	}
+	notaryRequestDuration.WithLabelValues(r.op.method).Observe(time.Since(start).Seconds())
+
+	var outcome string
+	switch {
+	case errors.Is(err, errPolicyRejected):
+		outcome = outcomeRejected
+	case err != nil:
+		outcome = outcomeFailed
+	case isMain:
+		outcome = outcomeMain
+	default:
+		outcome = outcomeFallback
+	}
+	outcome = p.s.finishRequest(r.event.NotaryRequest.Hash(), outcome, err) // в блок могла попасть не та tx, которую выбрал backend
+	notaryRequests.WithLabelValues(r.op.method, outcome).Inc()
+
+	log := p.s.log.With(zap.String("hash", r.event.NotaryRequest.Hash().String()), zap.String("method", r.op.method),
+		zap.Int("worker", i), zap.Bool("signed_main", isMain), zap.String("outcome", outcome),
+		zap.String("token", r.tokenName), zap.Duration("took", time.Since(start)))
+	switch {
+	case errors.Is(err, errPolicyRejected):
+		log.Info("notary request rejected", zap.Error(err))
+	case err != nil:
+		log.Error("proceed notary tx", zap.Error(err))
+	default:
+		log.Info("proceed notary tx")
+	}
+}
@@ -5,12 +5,10 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
-	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"os/signal"
@@ -24,9 +22,9 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
-	"github.com/nspcc-dev/neo-go/pkg/encoding/base58"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/actor"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/invoker"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/notary"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/unwrap"
 	"github.com/nspcc-dev/neo-go/pkg/util"
@@ -36,13 +34,14 @@ import (
 )
 
 const (
-	cfgRPCEndpoint   = "rpc_endpoint"
-	cfgRPCEndpointWC = "rpc_endpoint_ws"
-	cfgBackendKey    = "backend_key"
-	cfgWallet        = "wallet"
-	cfgPassword      = "password"
-	cfgNnsContract   = "nns_contract"
-	cfgBackendURL    = "backend_url"
+	cfgRPCEndpoint = "rpc_endpoint"
+	cfgBackendKey  = "backend_key"
+	cfgWallet      = "wallet"
+	cfgPassword    = "password"
+	cfgNnsContract = "nns_contract"
+	cfgBackendURL  = "backend_url"
+
+	apiAuthMessagePrefix = "auction api:"
 )
 
 var listOfTickets []string
@@ -65,8 +64,6 @@ func main() {
 	// или пользователей с нодой bc, rpc_endpoint = "http://localhost:30333"
 	die(err)
 
-	rpcEndpointWc := viper.GetString(cfgRPCEndpointWC)
-
 	backendKey, err := keys.NewPublicKeyFromString(viper.GetString(cfgBackendKey)) // получаем PK backendа, у него есть кошелек
 	die(err)
 
@@ -77,11 +74,13 @@ func main() {
 	err = acc.Decrypt(viper.GetString(cfgPassword), w.Scrypt) // подтверждаем его паролем
 	die(err)
 
-	nnsContractHash := viper.GetString(cfgNnsContract)
+	nnsContractHash, err := util.Uint160DecodeStringLE(viper.GetString(cfgNnsContract))
+	die(err)
 
-	nftContractHash, err := GetNnsResolve("nft.auc", nnsContractHash, viper.GetString(cfgRPCEndpoint))
+	inv := invoker.New(rpcCli, nil)
+	nftContractHash, err := GetNnsResolve("nft.auc", nnsContractHash, inv)
 	die(err)
-	auctionContractHash, err := GetNnsResolve("auc.auc", nnsContractHash, viper.GetString(cfgRPCEndpoint))
+	auctionContractHash, err := GetNnsResolve("auc.auc", nnsContractHash, inv)
 	die(err)
 
 	numbers := make([]int, 100) // создание списка имен пока еще свободных nft
@@ -93,7 +92,7 @@ func main() {
 		listOfTickets[i] = strconv.Itoa(num)
 	}
 
-	go ListenNotifications(ctx, rpcEndpointWc, auctionContractHash.StringLE())
+	go ListenEvents(ctx, viper.GetString(cfgBackendURL)) // события аукционов присылает backend
 
 	in := make(chan string)
 
@@ -139,7 +138,11 @@ func main() {
 					fmt.Printf("Error converting bet number to integer: %v\n", err)
 					return
 				}
-				die(makeNotaryRequestStartAuction(backendKey, acc, rpcCli, auctionContractHash, nftId, initBet)) // создание НЗ (оборачивает main tx, которая состоит в вызове метода контракта)
+				auctionName := "" // необязательное имя, по которому аукцион будет доступен в nns как <имя>.auc.auc
+				if len(args) > 3 {
+					auctionName = args[3]
+				}
+				die(makeNotaryRequestStartAuction(backendKey, acc, rpcCli, auctionContractHash, nftId, initBet, auctionName)) // создание НЗ (оборачивает main tx, которая состоит в вызове метода контракта)
 			case "getNFT":
 				die(makeNotaryRequestGetNft(backendKey, acc, rpcCli, nftContractHash))
 			case "makeBet":
@@ -159,116 +162,69 @@ func main() {
 	}
 }
 
-func GetNnsResolve(domainName string, nnsContractHash string, rpcEndpoint string) (util.Uint160, error) {
-
-	type StackItem struct {
-		Type  string `json:"type"`
-		Value []struct {
-			Type  string `json:"type"`
-			Value string `json:"value"`
-		} `json:"value"`
-	}
-
-	type RPCResponse struct {
-		ID      int    `json:"id"`
-		JSONRPC string `json:"jsonrpc"`
-		Result  struct {
-			State         string        `json:"state"`
-			GasConsumed   string        `json:"gasconsumed"`
-			Script        string        `json:"script"`
-			Stack         []StackItem   `json:"stack"`
-			Exception     interface{}   `json:"exception"`
-			Notifications []interface{} `json:"notifications"`
-		} `json:"result"`
-	}
-
-	rpcRequest := map[string]interface{}{
-		"jsonrpc": "2.0",
-		"method":  "invokefunction",
-		"params": []interface{}{
-			nnsContractHash,
-			"resolve",
-			[]interface{}{
-				map[string]interface{}{
-					"type":  "String",
-					"value": domainName,
-				},
-				map[string]interface{}{
-					"type":  "Integer",
-					"value": "16",
-				},
-			},
-		},
-		"id": 1,
-	}
-
-	jsonData, err := json.Marshal(rpcRequest)
+func GetNnsResolve(domainName string, nnsContractHash util.Uint160, inv *invoker.Invoker) (util.Uint160, error) {
+	contractHash, err := unwrap.Uint160(inv.Call(nnsContractHash, "resolveHash", domainName)) // HASH160 запись nns сразу отдает хэш контракта
 	if err != nil {
-		fmt.Printf("Ошибка кодирования JSON: %v\n", err)
-		return util.Uint160{}, err
+		return util.Uint160{}, fmt.Errorf("nns resolve %s: %w", domainName, err)
 	}
 
-	// Отправка HTTP POST-запроса
-	resp, err := http.Post(rpcEndpoint, "application/json", bytes.NewBuffer(jsonData))
+	return contractHash, nil
+}
+
+func claimNotaryDeposit(acc *wallet.Account) error {
+	// backend слушает http запросы на порту 5555, туда и говорим о своей просьбе накинуть нам НД,
+	// backend пополнит его, только если он заканчивается
+	resp, err := signedRequest(acc, http.MethodPost, "/notary-deposit/"+acc.Address, nil)
 	if err != nil {
-		fmt.Printf("Ошибка отправки запроса: %v\n", err)
-		return util.Uint160{}, err
+		return err
 	}
 	defer resp.Body.Close()
 
-	// Чтение ответа
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Printf("Ошибка чтения ответа: %v\n", err)
-		return util.Uint160{}, err
+	if resp.StatusCode == http.StatusTooManyRequests { // НД запрашивается перед каждой командой, скорее всего он еще есть
+		fmt.Println("notary deposit request is rate limited, skip it")
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("notary deposit failed: %d, %s", resp.StatusCode, resp.Status)
 	}
 
-	var rpcResponse RPCResponse
+	return nil
+}
 
-	// Парсим JSON-ответ
-	err = json.Unmarshal([]byte(string(body)), &rpcResponse)
+// signedRequest sends request to the backend API signed with the wallet key.
+func signedRequest(acc *wallet.Account, method, path string, body []byte) (*http.Response, error) {
+	backendURL := viper.GetString(cfgBackendURL)
+
+	resp, err := http.Get(backendURL + "/auth/nonce/" + acc.Address) // одноразовый nonce, подписав его, докажем, что адрес наш
 	if err != nil {
-		fmt.Println("Ошибка парсинга JSON:", err)
-		return util.Uint160{}, err
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get nonce: %d, %s", resp.StatusCode, resp.Status)
 	}
 
-	// Извлекаем нужное значение из стека
-	if len(rpcResponse.Result.Stack) > 0 && len(rpcResponse.Result.Stack[0].Value) > 0 {
-		value := rpcResponse.Result.Stack[0].Value[0].Value
-		decoded64, err := base64.StdEncoding.DecodeString(value)
-		if err != nil {
-			fmt.Println("Ошибка при декодировании Base64:", err)
-		}
-
-		decoded58, err := base58.CheckDecode(string(decoded64))
-		if err != nil {
-			return util.Uint160{}, err
-		}
-		contractHashStr := hex.EncodeToString(decoded58)[2:]
-		contractHash, err := util.Uint160DecodeStringBE(contractHashStr)
-		if err != nil {
-			return util.Uint160{}, err
-		}
-
-		return contractHash, nil
+	var nonce struct {
+		Nonce string `json:"nonce"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&nonce); err != nil {
+		return nil, fmt.Errorf("decode nonce: %w", err)
 	}
 
-	return util.Uint160{}, fmt.Errorf("stack is empty or unexpected structure")
-}
+	bodyHash := sha256.Sum256(body) // то же сообщение собирает backend
+	msg := []byte(apiAuthMessagePrefix + nonce.Nonce + ":" + method + ":" + path + ":" + hex.EncodeToString(bodyHash[:]))
 
-func claimNotaryDeposit(acc *wallet.Account) error {
-	resp, err := http.Get(viper.GetString(cfgBackendURL) + "/notary-deposit/" + acc.Address) // формируем http запрос к backendу, он слушает http
-	// запросы на порту 5555, туда и говорим о своей просьбу накинуть нам НД
+	req, err := http.NewRequest(method, backendURL+path, bytes.NewReader(body))
 	if err != nil {
-		return err
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("notary deposit failed: %d, %s", resp.StatusCode, resp.Status)
+		return nil, err
 	}
+	req.Header.Set("X-Neo-Public-Key", hex.EncodeToString(acc.PublicKey().Bytes()))
+	req.Header.Set("X-Neo-Nonce", nonce.Nonce)
+	req.Header.Set("X-Neo-Signature", hex.EncodeToString(acc.PrivateKey().Sign(msg)))
 
-	return nil
+	return http.DefaultClient.Do(req)
 }
+
 func makeNotaryRequestPreProcessing(acc *wallet.Account, backendKey *keys.PublicKey, rpcCli *rpcclient.Client) (*notary.Actor, error) {
 	coSigners := []actor.SignerAccount{
 		{
@@ -350,7 +306,7 @@ func makeNotaryRequestGetNft(backendKey *keys.PublicKey, acc *wallet.Account, rp
 	return nil
 }
 
-func makeNotaryRequestStartAuction(backendKey *keys.PublicKey, acc *wallet.Account, rpcCli *rpcclient.Client, contractAuctionHash util.Uint160, nftId string, initBet int) error {
+func makeNotaryRequestStartAuction(backendKey *keys.PublicKey, acc *wallet.Account, rpcCli *rpcclient.Client, contractAuctionHash util.Uint160, nftId string, initBet int, name string) error {
 	nAct, err := makeNotaryRequestPreProcessing(acc, backendKey, rpcCli)
 	if err != nil {
 		return fmt.Errorf("makeNotaryRequestPreProcessing: %w", err)
@@ -360,7 +316,7 @@ func makeNotaryRequestStartAuction(backendKey *keys.PublicKey, acc *wallet.Accou
 	if err != nil {
 		fmt.Printf("Invalid convertion nftId: %s", err)
 	}
-	tx, err := nAct.MakeTunedCall(contractAuctionHash, "start", nil, nil, acc.ScriptHash(), nftIdBytes, initBet) // tx = вызов метода start на
+	tx, err := nAct.MakeTunedCall(contractAuctionHash, "start", nil, nil, acc.ScriptHash(), nftIdBytes, initBet, name) // tx = вызов метода start на
 	// контракте auction
 	if err != nil {
 		return err
@@ -1,96 +1,109 @@
 package main
 
 import (
+	"bufio"
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
-
-	"github.com/gorilla/websocket"
+	"net/http"
+	"strings"
+	"time"
 )
 
-func ListenNotifications(ctx context.Context, url string, contractToListen string) {
-	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
-	if err != nil {
-		fmt.Print("Не удалось подключиться к WebSocket:", err)
-	}
-	defer conn.Close()
+const eventsReconnectDelay = 3 * time.Second
 
-	// Подписка на notifications контракта auction
-	subscribeMessage := map[string]interface{}{
-		"jsonrpc": "2.0",
-		"method":  "subscribe",
-		"params":  []interface{}{"notification_from_execution", map[string]string{"contract": contractToListen}},
-		"id":      1,
-	}
-
-	err = conn.WriteJSON(subscribeMessage)
-	if err != nil {
-		fmt.Printf("Ошибка при отправке подписки: %v", err)
-	}
+// auctionEvent is an event from the backend /events stream.
+type auctionEvent struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Auction    int64  `json:"auction"`
+	Block      uint32 `json:"block"`
+	Organizer  string `json:"organizer"`
+	Bidder     string `json:"bidder"`
+	Winner     string `json:"winner"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Lot        string `json:"lot"`
+	InitialBet int64  `json:"initialBet"`
+	Bet        int64  `json:"bet"`
+}
 
-	// Чтение уведомлений
+// ListenEvents prints auction events streamed by the backend, after a disconnect
+// it reconnects and continues from the last received event.
+func ListenEvents(ctx context.Context, backendURL string) {
+	var lastID string
 	for {
+		err := readEvents(ctx, backendURL, &lastID)
+		if ctx.Err() != nil {
+			return
+		}
+		fmt.Println("Поток событий прерван:", err)
+
 		select {
 		case <-ctx.Done():
-			die(ctx.Err())
-		default:
-			_, message, err := conn.ReadMessage()
-			if err != nil {
-				fmt.Println("read:", err)
-				break
-			}
-			var result map[string]interface{}
-			err = json.Unmarshal([]byte(message), &result)
-			if err != nil {
-				fmt.Println("Error parsing JSON:", err)
-				continue
-			}
-
-			params, ok := result["params"].([]interface{})
-			if !ok || len(params) == 0 {
-				continue
-			}
-
-			firstParam, ok := params[0].(map[string]interface{})
-			if !ok {
-				fmt.Println("Invalid param type")
-				continue
-			}
+			return
+		case <-time.After(eventsReconnectDelay):
+		}
+	}
+}
 
-			state, ok := firstParam["state"].(map[string]interface{})
-			if !ok {
-				fmt.Println("Invalid state type")
-				continue
-			}
+func readEvents(ctx context.Context, backendURL string, lastID *string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, backendURL+"/events", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if *lastID != "" { // backend отдаст то, что мы пропустили
+		req.Header.Set("Last-Event-ID", *lastID)
+	}
 
-			values, ok := state["value"].([]interface{})
-			if !ok || len(values) == 0 {
-				fmt.Println("No values found")
-				continue
-			}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
 
-			// Извлекаем Base64 строку
-			firstValue, ok := values[0].(map[string]interface{})
-			if !ok {
-				fmt.Println("Invalid value type")
-				continue
-			}
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		data, ok := strings.CutPrefix(sc.Text(), "data: ")
+		if !ok { // id и event дублируются в data, комментарии - keep-alive
+			continue
+		}
 
-			base64String, ok := firstValue["value"].(string)
-			if !ok {
-				fmt.Println("Base64 value is missing or not a string")
-				continue
-			}
+		var e auctionEvent
+		if err = json.Unmarshal([]byte(data), &e); err != nil {
+			fmt.Println("Error parsing event:", err)
+			continue
+		}
+		*lastID = e.ID
+		printEvent(e)
+	}
+	if err = sc.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("stream closed by backend")
+}
 
-			// Декодируем Base64
-			decodedBytes, err := base64.StdEncoding.DecodeString(base64String)
-			if err != nil {
-				fmt.Println("Error decoding Base64:", err)
-				continue
-			}
-			decodedString := string(decodedBytes)
-			fmt.Print("\nNOTIFICATION:", decodedString, "\n\n")
+func printEvent(e auctionEvent) {
+	var msg string
+	switch e.Type {
+	case "started":
+		msg = fmt.Sprintf("Аукцион %d начат пользователем %s, лот %s, начальная ставка %d", e.Auction, e.Organizer, e.Lot, e.InitialBet)
+	case "bid":
+		msg = fmt.Sprintf("В аукционе %d пользователь %s сделал ставку %d", e.Auction, e.Bidder, e.Bet)
+	case "finished":
+		msg = fmt.Sprintf("Аукцион %d завершен, победитель %s, ставка %d", e.Auction, e.Winner, e.Bet)
+	case "transfer":
+		from := e.From
+		if from == "" {
+			from = "(минт)"
 		}
+		msg = fmt.Sprintf("nft %s передан от %s к %s", e.Lot, from, e.To)
+	default:
+		return
 	}
+	fmt.Print("\nNOTIFICATION (блок ", e.Block, "): ", msg, "\n\n")
 }
@@ -0,0 +1,73 @@
+#!/usr/bin/env bash
+# Проверка DNS шлюза на локальной ноде: регистрирует в nns тестовое имя, запускает
+# шлюз и сверяет его ответы. Запускать из contracts/auction, нужны dig и nns с зоной $ZONE
+#   ./dns/check.sh
+set -euo pipefail
+
+ZONE=${ZONE:-auc}
+DNS_CONFIG=${DNS_CONFIG:-dns/config.yml}
+NNSCLI_CONFIG=${NNSCLI_CONFIG:-nnscli/config.yml}
+SERVER=${SERVER:-127.0.0.1}
+PORT=${PORT:-5353}
+
+name="check$(date +%s).$ZONE"
+failed=0
+
+nnscli() {
+	go run ./nnscli -c "$NNSCLI_CONFIG" "$@"
+}
+
+# status <name> <type> печатает rcode ответа шлюза
+status() {
+	dig @"$SERVER" -p "$PORT" +noall +comments "$1" "$2" | sed -n 's/.*status: \([A-Z]*\),.*/\1/p'
+}
+
+# answer <name> <type> печатает записи ответа шлюза
+answer() {
+	dig @"$SERVER" -p "$PORT" +short "$1" "$2" | sort | tr '\n' ' ' | sed 's/ $//'
+}
+
+expect() {
+	if [ "$2" == "$3" ]; then
+		echo "ok   $1"
+	else
+		echo "FAIL $1: expected '$3', got '$2'"
+		failed=1
+	fi
+}
+
+# wait_block ждет следующего блока, после него шлюз сбрасывает кэш
+wait_block() {
+	local rpc height
+	rpc=$(sed -n 's/^rpc_endpoint: *"\(.*\)"/\1/p' "$DNS_CONFIG")
+	height=$(curl -s -d '{"jsonrpc":"2.0","id":1,"method":"getblockcount","params":[]}' "$rpc" | sed 's/.*"result":\([0-9]*\).*/\1/')
+	while [ "$(curl -s -d '{"jsonrpc":"2.0","id":1,"method":"getblockcount","params":[]}' "$rpc" | sed 's/.*"result":\([0-9]*\).*/\1/')" == "$height" ]; do
+		sleep 1
+	done
+	sleep 2 # шлюз опрашивает высоту раз в block_poll_interval
+}
+
+echo "register $name"
+nnscli register "$name"
+nnscli add-record "$name" A 10.0.0.1
+nnscli add-record "$name" TXT "dns check"
+nnscli add-record "alias.$name" CNAME "$name"
+
+go run ./dns "$DNS_CONFIG" >/dev/null 2>&1 &
+gateway=$!
+trap 'kill $gateway 2>/dev/null' EXIT
+sleep 5
+
+expect "A record" "$(answer "$name" A)" "10.0.0.1"
+expect "TXT record" "$(answer "$name" TXT)" '"dns check"'
+expect "CNAME chain" "$(answer "alias.$name" A)" "10.0.0.1 $name."
+expect "no records of the type" "$(status "$name" AAAA)" "NOERROR"
+expect "unregistered subdomain" "$(status "missing.$name" A)" "NXDOMAIN"
+expect "unregistered zone" "$(status "$name-missing" A)" "NXDOMAIN"
+
+answer "$name" A >/dev/null # ответ попал в кэш
+nnscli add-record "$name" A 10.0.0.2
+wait_block
+expect "cache is reset by the new block" "$(answer "$name" A)" "10.0.0.1 10.0.0.2"
+
+exit $failed
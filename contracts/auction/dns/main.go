@@ -0,0 +1,429 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/invoker"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/unwrap"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const (
+	cfgRPCEndpoint       = "rpc_endpoint"
+	cfgNnsContract       = "nns_contract"
+	cfgListenAddress     = "listen_address"
+	cfgDefaultTTL        = "default_ttl"
+	cfgBlockPollInterval = "block_poll_interval"
+	cfgCacheSize         = "cache_size"
+)
+
+const (
+	maxRedirects = 2 // столько же переходов по CNAME, сколько позволяет resolve в nns
+
+	defaultTTL               = 300
+	defaultBlockPollInterval = time.Second
+	defaultCacheSize         = 10000
+)
+
+func main() {
+	ctx, _ := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+
+	if len(os.Args) != 2 { // go run ./dns dns/config.yml
+		die(fmt.Errorf("invalid args: %v", os.Args))
+	}
+
+	viper.GetViper().SetConfigType("yml")
+
+	f, err := os.Open(os.Args[1])
+	die(err)
+	die(viper.GetViper().ReadConfig(f))
+	die(f.Close())
+
+	g, err := NewGateway(ctx)
+	die(err)
+
+	die(g.Listen(ctx))
+}
+
+// Gateway answers DNS queries with records stored in NNS.
+type Gateway struct {
+	rpcCli     *rpcclient.Client
+	inv        *invoker.Invoker
+	nnsHash    util.Uint160
+	defaultTTL uint32
+	cacheSize  int
+	log        *zap.Logger
+
+	mu     sync.Mutex
+	height uint32               // высота, на которой были получены ответы из кэша
+	cache  map[cacheKey]*answer // ответы живут до следующего блока, пока состояние nns не могло поменяться
+}
+
+type cacheKey struct {
+	name  string
+	qtype uint16
+}
+
+type answer struct {
+	rcode int
+	rrs   []dns.RR
+	ns    []dns.RR
+}
+
+func NewGateway(ctx context.Context) (*Gateway, error) {
+	rpcCli, err := rpcclient.New(ctx, viper.GetString(cfgRPCEndpoint), rpcclient.Options{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = rpcCli.Init(); err != nil {
+		return nil, err
+	}
+
+	nnsHash, err := util.Uint160DecodeStringLE(viper.GetString(cfgNnsContract))
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := viper.GetUint32(cfgDefaultTTL)
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+
+	cacheSize := viper.GetInt(cfgCacheSize)
+	if cacheSize <= 0 {
+		cacheSize = defaultCacheSize
+	}
+
+	log, err := zap.NewDevelopment()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Gateway{
+		rpcCli:     rpcCli,
+		inv:        invoker.New(rpcCli, nil), // только читаем, подписанты не нужны
+		nnsHash:    nnsHash,
+		defaultTTL: ttl,
+		cacheSize:  cacheSize,
+		log:        log,
+		cache:      make(map[cacheKey]*answer),
+	}, nil
+}
+
+func (g *Gateway) Listen(ctx context.Context) error {
+	pollInterval := viper.GetDuration(cfgBlockPollInterval)
+	if pollInterval <= 0 {
+		pollInterval = defaultBlockPollInterval
+	}
+	go g.runBlockWatcher(ctx, pollInterval)
+
+	addr := viper.GetString(cfgListenAddress)
+	servers := []*dns.Server{
+		{Addr: addr, Net: "udp", Handler: g},
+		{Addr: addr, Net: "tcp", Handler: g},
+	}
+
+	errCh := make(chan error, len(servers))
+	for _, srv := range servers {
+		go func(srv *dns.Server) {
+			g.log.Info("start listening", zap.String("net", srv.Net), zap.String("address", srv.Addr))
+			errCh <- srv.ListenAndServe()
+		}(srv)
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-errCh:
+	}
+
+	for _, srv := range servers {
+		if shutdownErr := srv.Shutdown(); shutdownErr != nil {
+			g.log.Warn("shutdown dns server", zap.String("net", srv.Net), zap.Error(shutdownErr))
+		}
+	}
+
+	return err
+}
+
+// runBlockWatcher drops cached answers as soon as a new block is accepted.
+func (g *Gateway) runBlockWatcher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			height, err := g.rpcCli.GetBlockCount()
+			if err != nil {
+				g.log.Warn("get block count", zap.Error(err))
+				continue
+			}
+
+			g.mu.Lock()
+			if height != g.height {
+				g.height = height
+				g.cache = make(map[cacheKey]*answer)
+			}
+			g.mu.Unlock()
+		}
+	}
+}
+
+// ServeDNS implements dns.Handler.
+func (g *Gateway) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	m := new(dns.Msg)
+	m.SetReply(req)
+	m.Authoritative = true
+
+	if len(req.Question) != 1 {
+		m.Rcode = dns.RcodeFormatError
+	} else {
+		q := req.Question[0]
+		ans, err := g.lookup(strings.ToLower(q.Name), q.Qtype)
+		if err != nil {
+			g.log.Error("lookup", zap.String("name", q.Name), zap.Uint16("type", q.Qtype), zap.Error(err))
+			m.Rcode = dns.RcodeServerFailure
+		} else {
+			m.Rcode = ans.rcode
+			m.Answer = ans.rrs
+			m.Ns = ans.ns
+		}
+	}
+
+	if err := w.WriteMsg(m); err != nil {
+		g.log.Error("write response", zap.Error(err))
+	}
+}
+
+func (g *Gateway) lookup(fqdn string, qtype uint16) (*answer, error) {
+	key := cacheKey{name: fqdn, qtype: qtype}
+
+	g.mu.Lock()
+	height := g.height
+	ans, ok := g.cache[key]
+	g.mu.Unlock()
+	if ok {
+		return ans, nil
+	}
+
+	ans, err := g.resolve(strings.TrimSuffix(fqdn, "."), qtype)
+	if err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	// пока разрешали имя, мог прийти новый блок, тогда ответ мог быть получен
+	// на старом состоянии и в новый кэш его класть нельзя
+	if g.height == height && len(g.cache) < g.cacheSize {
+		g.cache[key] = ans
+	}
+	g.mu.Unlock()
+
+	return ans, nil
+}
+
+// resolve follows CNAME records the same way nns resolve does, but keeps
+// every step of the chain, so that DNS clients get proper CNAME answers.
+func (g *Gateway) resolve(name string, qtype uint16) (*answer, error) {
+	ans := &answer{rcode: dns.RcodeSuccess}
+
+	for redirect := 0; redirect <= maxRedirects; redirect++ {
+		records, found, err := g.getRecords(name, qtype)
+		if err != nil {
+			return nil, err
+		}
+		if found && len(records) == 0 { // для незарегистрированного поддомена nns отдает записи пустыми
+			if found, err = g.nameExists(name); err != nil {
+				return nil, err
+			}
+		}
+		if !found {
+			if redirect == 0 {
+				ans.rcode = dns.RcodeNameError
+			}
+			return ans, nil
+		}
+
+		soa, err := g.findSOA(name)
+		if err != nil {
+			return nil, err
+		}
+		ttl := g.defaultTTL
+		if soa != nil {
+			ttl = soa.Minttl
+		}
+
+		for _, data := range records {
+			if rr := newRR(name, qtype, ttl, data); rr != nil {
+				ans.rrs = append(ans.rrs, rr)
+			}
+		}
+		if len(records) != 0 || qtype == dns.TypeCNAME {
+			return ans, nil
+		}
+
+		cnames, _, err := g.getRecords(name, dns.TypeCNAME)
+		if err != nil {
+			return nil, err
+		}
+		if len(cnames) == 0 {
+			if soa != nil && len(ans.rrs) == 0 { // имя есть, а записей такого типа нет
+				ans.ns = append(ans.ns, soa)
+			}
+			return ans, nil
+		}
+
+		ans.rrs = append(ans.rrs, newRR(name, dns.TypeCNAME, ttl, cnames[0]))
+		name = cnames[0]
+	}
+
+	return ans, nil
+}
+
+// findSOA returns SOA record of the zone the name belongs to or nil if there is none.
+func (g *Gateway) findSOA(name string) (*dns.SOA, error) {
+	labels := dns.SplitDomainName(name)
+	for i := range labels {
+		zone := strings.Join(labels[i:], ".")
+		records, found, err := g.getRecords(zone, dns.TypeSOA)
+		if err != nil {
+			return nil, err
+		}
+		if found && len(records) != 0 {
+			soa, _ := newRR(zone, dns.TypeSOA, 0, records[0]).(*dns.SOA)
+			return soa, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// getRecords calls getRecords of NNS, found is false if there is no such
+// domain or it has expired (nns panics in this case).
+func (g *Gateway) getRecords(name string, typ uint16) ([]string, bool, error) {
+	res, err := g.inv.Call(g.nnsHash, "getRecords", name, int64(typ))
+	if err != nil {
+		return nil, false, err
+	}
+	if res.State != vmstate.Halt.String() {
+		return nil, false, nil
+	}
+	if len(res.Stack) == 1 && res.Stack[0].Type() == stackitem.AnyT { // пустой список записей приходит как Null
+		return nil, true, nil
+	}
+
+	records, err := unwrap.ArrayOfUTF8Strings(res, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("getRecords %s: %w", name, err)
+	}
+
+	return records, true, nil
+}
+
+// nameExists checks whether the name is registered or has records of its own: nns
+// getRecords doesn't fail for an unknown subdomain of a registered name.
+func (g *Gateway) nameExists(name string) (bool, error) {
+	res, err := g.inv.CallAndExpandIterator(g.nnsHash, "getAllRecords", 1, name)
+	if err != nil {
+		return false, err
+	}
+	if res.State != vmstate.Halt.String() {
+		return false, nil
+	}
+
+	records, err := unwrap.Array(res, nil)
+	if err != nil {
+		return false, fmt.Errorf("getAllRecords %s: %w", name, err)
+	}
+
+	return len(records) != 0, nil
+}
+
+// newRR converts NNS record data to DNS resource record, it returns nil
+// for record types that can't be represented in DNS.
+func newRR(name string, typ uint16, ttl uint32, data string) dns.RR {
+	hdr := dns.RR_Header{Name: dns.Fqdn(name), Rrtype: typ, Class: dns.ClassINET, Ttl: ttl}
+
+	switch typ {
+	case dns.TypeA:
+		return &dns.A{Hdr: hdr, A: net.ParseIP(data)}
+	case dns.TypeAAAA:
+		return &dns.AAAA{Hdr: hdr, AAAA: net.ParseIP(data)}
+	case dns.TypeCNAME:
+		return &dns.CNAME{Hdr: hdr, Target: dns.Fqdn(data)}
+	case dns.TypeTXT:
+		return &dns.TXT{Hdr: hdr, Txt: []string{data}}
+	case dns.TypeSOA:
+		soa, err := parseSOA(hdr, data)
+		if err != nil {
+			return nil
+		}
+		return soa
+	default:
+		return nil
+	}
+}
+
+// parseSOA parses NNS SOA record: "name email serial refresh retry expire ttl",
+// the serial is a block timestamp in milliseconds.
+func parseSOA(hdr dns.RR_Header, data string) (*dns.SOA, error) {
+	fields := strings.Fields(data)
+	if len(fields) != 7 {
+		return nil, errors.New("invalid soa record")
+	}
+
+	nums := make([]uint64, 5)
+	for i, f := range fields[2:] {
+		n, err := strconv.ParseUint(f, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid soa record: %w", err)
+		}
+		nums[i] = n
+	}
+
+	if hdr.Ttl == 0 {
+		hdr.Ttl = uint32(nums[4])
+	}
+
+	return &dns.SOA{
+		Hdr:     hdr,
+		Ns:      dns.Fqdn(fields[0]),
+		Mbox:    dns.Fqdn(strings.Replace(fields[1], "@", ".", 1)),
+		Serial:  uint32(nums[0] / 1000),
+		Refresh: uint32(nums[1]),
+		Retry:   uint32(nums[2]),
+		Expire:  uint32(nums[3]),
+		Minttl:  uint32(nums[4]),
+	}, nil
+}
+
+func die(err error) {
+	if err == nil {
+		return
+	}
+
+	debug.PrintStack()
+	_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	os.Exit(1)
+}
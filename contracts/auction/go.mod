@@ -10,10 +10,13 @@ require (
 	git.frostfs.info/TrueCloudLab/frostfs-node v0.44.6
 	git.frostfs.info/TrueCloudLab/frostfs-sdk-go v0.0.0-20241226115718-82e48c8a634d
 	git.frostfs.info/TrueCloudLab/hrw v1.2.1
-	github.com/gorilla/websocket v1.5.3
+	github.com/google/uuid v1.6.0
+	github.com/miekg/dns v1.1.62
 	github.com/nspcc-dev/neo-go v0.107.2
 	github.com/nspcc-dev/neo-go/pkg/interop v0.0.0-20241228090728-4d2b88dd9dbd
+	github.com/prometheus/client_golang v1.20.2
 	github.com/spf13/viper v1.19.0
+	go.etcd.io/bbolt v1.3.11
 	go.uber.org/zap v1.27.0
 )
 
@@ -34,7 +37,7 @@ require (
 	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.3.0 // indirect
 	github.com/fsnotify/fsnotify v1.7.0 // indirect
 	github.com/golang/snappy v0.0.4 // indirect
-	github.com/google/uuid v1.6.0 // indirect
+	github.com/gorilla/websocket v1.5.3 // indirect
 	github.com/hashicorp/golang-lru/v2 v2.0.7 // indirect
 	github.com/hashicorp/hcl v1.0.0 // indirect
 	github.com/holiman/uint256 v1.3.1 // indirect
@@ -47,7 +50,6 @@ require (
 	github.com/nspcc-dev/go-ordered-json v0.0.0-20240830112754-291b000d1f3b // indirect
 	github.com/nspcc-dev/rfc6979 v0.2.3 // indirect
 	github.com/pelletier/go-toml/v2 v2.2.2 // indirect
-	github.com/prometheus/client_golang v1.20.2 // indirect
 	github.com/prometheus/client_model v0.6.1 // indirect
 	github.com/prometheus/common v0.55.0 // indirect
 	github.com/prometheus/procfs v0.15.1 // indirect
@@ -61,7 +63,6 @@ require (
 	github.com/subosito/gotenv v1.6.0 // indirect
 	github.com/syndtr/goleveldb v1.0.1-0.20220721030215-126854af5e6d // indirect
 	github.com/twmb/murmur3 v1.1.8 // indirect
-	go.etcd.io/bbolt v1.3.11 // indirect
 	go.opentelemetry.io/otel v1.31.0 // indirect
 	go.opentelemetry.io/otel/trace v1.31.0 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
@@ -17,12 +17,13 @@ const (
 	balancePrefix = "b"
 	accountPrefix = "a"
 	tokenPrefix   = "t"
+	flagPrefix    = "f" // пометки токенов, у которых не удалось получить метаданные
 
 	ownerKey       = 'o'
 	totalSupplyKey = 's'
 
 	nnsSelfDomain         = "nft.auc"
-	nnsRecordType         = 16
+	nnsRecordType         = 80 // HASH160 record, it holds the address of the contract
 	nnsContractHashString = "NcCZaxnLkXvrd56DgpFSSBjhj2DqzH3jKP"
 )
 
@@ -104,6 +105,9 @@ func Properties(token []byte) map[string]string {
 		"name":    nft.Name,
 		"address": nft.Address,
 	}
+	if flag := storage.Get(ctx, mkFlagKey(token)); flag != nil {
+		result["flag"] = flag.(string)
+	}
 	return result
 }
 
@@ -243,6 +247,40 @@ func SetAddress(name string, address string) {
 	nft := getNFT(ctx, tokenID)
 	nft.Address = address
 	setNFT(ctx, tokenID, nft)
+	storage.Delete(ctx, mkFlagKey(tokenID)) // метаданные появились, пометка больше не нужна
+}
+
+// Flag marks the token whose metadata can't be obtained, the reason is shown in its properties.
+func Flag(name string, reason string) {
+	ctx := storage.GetContext()
+	if !runtime.CheckWitness(storage.Get(ctx, ownerKey).(interop.Hash160)) {
+		panic("not witnessed")
+	}
+
+	tokenID := crypto.Sha256([]byte(name))
+	getNFT(ctx, tokenID) // токен должен существовать
+	storage.Put(ctx, mkFlagKey(tokenID), reason)
+}
+
+// Burn destroys the token whose metadata can't be obtained.
+func Burn(name string) {
+	ctx := storage.GetContext()
+	if !runtime.CheckWitness(storage.Get(ctx, ownerKey).(interop.Hash160)) {
+		panic("not witnessed")
+	}
+
+	tokenID := crypto.Sha256([]byte(name))
+	nft := getNFT(ctx, tokenID)
+
+	storage.Delete(ctx, mkTokenKey(tokenID))
+	storage.Delete(ctx, mkFlagKey(tokenID))
+	addToBalance(ctx, nft.Owner, -1)
+	removeToken(ctx, nft.Owner, tokenID)
+
+	total := storage.Get(ctx, totalSupplyKey).(int) - 1
+	storage.Put(ctx, totalSupplyKey, total)
+
+	runtime.Notify("Transfer", nft.Owner, nil, 1, []byte(tokenID))
 }
 
 // mkAccountPrefix creates DB key-prefix for the account tokens specified
@@ -266,6 +304,12 @@ func mkTokenKey(tokenID []byte) []byte {
 	return append(res, tokenID...)
 }
 
+// mkFlagKey creates DB key for the token flag.
+func mkFlagKey(tokenID []byte) []byte {
+	res := []byte(flagPrefix)
+	return append(res, tokenID...)
+}
+
 // getBalanceOf returns the balance of an account using database key.
 func getBalanceOf(ctx storage.Context, balanceKey []byte) int {
 	val := storage.Get(ctx, balanceKey)
@@ -49,6 +49,11 @@ const (
 	prefixCountSubDomains byte = 0x24
 	// prefixAutoCreated contains a flag indicating whether the TLD domain was created automatically.
 	prefixAutoCreated = 0x25
+	// prefixReverse contains map from address to ReverseState with the name claimed for it.
+	prefixReverse byte = 0x26
+	// prefixReverseIndex contains map from (token key + address) to address,
+	// it is used to clean up reverse entries of the domain.
+	prefixReverseIndex byte = 0x27
 )
 
 // Values constraints.
@@ -65,6 +70,8 @@ const (
 	maxDomainNameLength = 255
 	// maxTXTRecordLength is the maximum length of the TXT domain record.
 	maxTXTRecordLength = 255
+	// hash160RecordLength is the length of the Neo N3 address in the HASH160 domain record.
+	hash160RecordLength = 34
 )
 
 // Other constants.
@@ -75,6 +82,8 @@ const (
 	millisecondsInYear = int64(365 * 24 * 3600 * 1000)
 	// errInvalidDomainName is an error message for invalid domain name format.
 	errInvalidDomainName = "invalid domain name format"
+	// addressVersion is the version byte of Neo N3 addresses.
+	addressVersion = 0x35
 )
 
 const (
@@ -91,6 +100,12 @@ type RecordState struct {
 	ID   byte
 }
 
+// ReverseState is a type that reverse entries are saved to.
+type ReverseState struct {
+	Name   string
+	Domain string
+}
+
 // Update updates NameService contract.
 func Update(nef []byte, manifest string, data any) {
 	checkCommittee()
@@ -200,6 +215,7 @@ func Transfer(to interop.Hash160, tokenID []byte, data any) bool {
 		ns.Owner = to
 		ns.Admin = nil
 		putNameStateWithKey(ctx, tokenKey, ns)
+		deleteReverseEntries(ctx, tokenKey)
 
 		// update `from` balance
 		updateBalance(ctx, tokenID, from, -1)
@@ -395,6 +411,7 @@ func register(ctx storage.Context, name string, owner interop.Hash160, email str
 		}
 		oldOwner = ns.Owner
 		updateBalance(ctx, []byte(name), oldOwner, -1)
+		deleteReverseEntries(ctx, tokenKey)
 	} else {
 		updateTotalSupply(ctx, +1)
 	}
@@ -412,6 +429,7 @@ func register(ctx storage.Context, name string, owner interop.Hash160, email str
 	updateBalance(ctx, []byte(name), owner, +1)
 	postTransfer(oldOwner, owner, []byte(name), nil)
 	runtime.Notify("RegisterDomain", name)
+	runtime.Notify("DomainRegistered", name, owner)
 	return true
 }
 
@@ -472,6 +490,8 @@ func checkBaseRecords(typ RecordType, data string) bool {
 		return len(data) <= maxTXTRecordLength
 	case AAAA:
 		return checkIPv6(data)
+	case HASH160:
+		return checkHash160(data)
 	default:
 		panic("unsupported record type")
 	}
@@ -525,7 +545,9 @@ func deleteRecords(ctx storage.Context, name string, typ RecordType) {
 	records := storage.Find(ctx, recordsKey, storage.KeysOnly)
 	for iterator.Next(records) {
 		r := iterator.Value(records).(string)
+		rs := std.Deserialize(storage.Get(ctx, r).([]byte)).(RecordState)
 		storage.Delete(ctx, r)
+		runtime.Notify("RecordDeleted", name, typ, rs.Data)
 	}
 	updateSoaSerial(ctx, tokenID)
 	runtime.Notify("DeleteRecords", name, typ)
@@ -572,6 +594,7 @@ func deleteRecord(ctx storage.Context, tokenId []byte, name string, typ RecordTy
 
 	storage.Delete(ctx, previousKey)
 	runtime.Notify("DeleteRecord", name, typ)
+	runtime.Notify("RecordDeleted", name, typ, data)
 	return true
 }
 
@@ -634,6 +657,8 @@ func deleteDomain(ctx storage.Context, name string) {
 	deleteRecords(ctx, name, TXT)
 	deleteRecords(ctx, name, A)
 	deleteRecords(ctx, name, AAAA)
+	deleteRecords(ctx, name, HASH160)
+	deleteReverseEntries(ctx, getTokenKey([]byte(name)))
 	storage.Delete(ctx, nsKey)
 	storage.Delete(ctx, append([]byte{prefixRoot}, []byte(name)...))
 
@@ -648,6 +673,7 @@ func deleteDomain(ctx storage.Context, name string) {
 	}
 
 	runtime.Notify("DeleteDomain", name)
+	runtime.Notify("DomainDeleted", name)
 }
 
 // Resolve resolves given name (not more then three redirects are allowed).
@@ -656,6 +682,101 @@ func Resolve(name string, typ RecordType) []string {
 	return resolve(ctx, nil, name, typ, 2)
 }
 
+// ResolveHash resolves given name to the script hash from its HASH160 record,
+// CNAME records are followed the same way Resolve does it.
+func ResolveHash(name string) interop.Hash160 {
+	if len(name) == 0 {
+		panic("invalid name")
+	}
+	if name[len(name)-1] == '.' {
+		name = name[:len(name)-1]
+	}
+
+	ctx := storage.GetReadOnlyContext()
+	for redirect := 2; redirect >= 0; redirect-- {
+		cname := ""
+		records := getAllRecords(ctx, name)
+		for iterator.Next(records) {
+			r := iterator.Value(records).(RecordState)
+			if r.Type == HASH160 {
+				return hash160FromAddress(r.Data)
+			}
+			if r.Type == CNAME {
+				cname = r.Data
+			}
+		}
+		if cname == "" {
+			break
+		}
+		name = cname
+	}
+	panic("hash160 record not found: " + name)
+}
+
+// SetReverse claims reverse entry for the given address, so that ReverseResolve
+// returns the name for it. Both the address and the domain admin must witness it.
+func SetReverse(name string, addr interop.Hash160) {
+	if !isValid(addr) {
+		panic("invalid address")
+	}
+	if !runtime.CheckWitness(addr) {
+		panic("not witnessed by address")
+	}
+	tokenID := tokenIDFromName(name)
+	ctx := storage.GetContext()
+	ns := getNameState(ctx, []byte(tokenID))
+	ns.checkAdmin()
+
+	deleteReverse(ctx, addr)
+	rs := ReverseState{
+		Name:   name,
+		Domain: tokenID,
+	}
+	storage.Put(ctx, append([]byte{prefixReverse}, addr...), std.Serialize(rs))
+	storage.Put(ctx, getReverseIndexKey(getTokenKey([]byte(tokenID)), addr), addr)
+}
+
+// DeleteReverse removes reverse entry of the given address. It can be done
+// either by the address itself or by the admin of the claimed domain.
+func DeleteReverse(addr interop.Hash160) {
+	if !isValid(addr) {
+		panic("invalid address")
+	}
+	ctx := storage.GetContext()
+	if !runtime.CheckWitness(addr) {
+		rs := getReverseState(ctx, addr)
+		if rs == nil {
+			panic("reverse entry not found")
+		}
+		ns := getNameState(ctx, []byte(rs.(ReverseState).Domain))
+		ns.checkAdmin()
+	}
+	deleteReverse(ctx, addr)
+}
+
+// ReverseResolve returns the name claimed for the given address or an empty
+// string if there is no such entry or the domain has expired.
+func ReverseResolve(addr interop.Hash160) string {
+	if !isValid(addr) {
+		panic("invalid address")
+	}
+	ctx := storage.GetReadOnlyContext()
+	rs := getReverseState(ctx, addr)
+	if rs == nil {
+		return ""
+	}
+	r := rs.(ReverseState)
+	nsBytes := storage.Get(ctx, append([]byte{prefixName}, getTokenKey([]byte(r.Domain))...))
+	if nsBytes == nil {
+		return ""
+	}
+	ns := std.Deserialize(nsBytes.([]byte)).(NameState)
+	if int64(runtime.GetTime()) >= ns.Expiration {
+		return ""
+	}
+	return r.Name
+}
+
 // GetAllRecords returns an Iterator with RecordState items for the given name.
 func GetAllRecords(name string) iterator.Iterator {
 	tokenID := []byte(tokenIDFromName(name))
@@ -697,6 +818,40 @@ func postTransfer(from, to interop.Hash160, tokenID []byte, data any) {
 	}
 }
 
+// getReverseState returns reverse entry of the address or nil if there is none.
+func getReverseState(ctx storage.Context, addr interop.Hash160) any {
+	data := storage.Get(ctx, append([]byte{prefixReverse}, addr...))
+	if data == nil {
+		return nil
+	}
+	return std.Deserialize(data.([]byte)).(ReverseState)
+}
+
+// deleteReverse removes reverse entry of the address together with its index.
+func deleteReverse(ctx storage.Context, addr interop.Hash160) {
+	rs := getReverseState(ctx, addr)
+	if rs == nil {
+		return
+	}
+	tokenKey := getTokenKey([]byte(rs.(ReverseState).Domain))
+	storage.Delete(ctx, getReverseIndexKey(tokenKey, addr))
+	storage.Delete(ctx, append([]byte{prefixReverse}, addr...))
+}
+
+// deleteReverseEntries removes all reverse entries claimed for the domain,
+// it's used when the domain changes its owner or is deleted.
+func deleteReverseEntries(ctx storage.Context, tokenKey []byte) {
+	it := storage.Find(ctx, append([]byte{prefixReverseIndex}, tokenKey...), storage.ValuesOnly)
+	for iterator.Next(it) {
+		deleteReverse(ctx, iterator.Value(it).([]byte))
+	}
+}
+
+// getReverseIndexKey returns the key used to index reverse entries of the domain.
+func getReverseIndexKey(tokenKey []byte, addr interop.Hash160) []byte {
+	return append(append([]byte{prefixReverseIndex}, tokenKey...), addr...)
+}
+
 // getTotalSupply returns total supply from storage.
 func getTotalSupply(ctx storage.Context) int {
 	val := storage.Get(ctx, []byte{prefixTotalSupply})
@@ -771,6 +926,8 @@ func putRecord(ctx storage.Context, tokenId []byte, name string, typ RecordType,
 	if recBytes == nil {
 		panic("invalid record id")
 	}
+	old := std.Deserialize(recBytes.([]byte)).(RecordState)
+	runtime.Notify("RecordDeleted", name, typ, old.Data)
 
 	storeRecord(ctx, recordKey, name, typ, id, data)
 }
@@ -811,6 +968,7 @@ func addRecord(ctx storage.Context, tokenId []byte, name string, typ RecordType,
 			var oldOwner interop.Hash160
 			updateBalance(ctx, []byte(name), nsOriginal.Owner, +1)
 			postTransfer(oldOwner, nsOriginal.Owner, []byte(name), nil)
+			runtime.Notify("DomainRegistered", globalDomain, nsOriginal.Owner)
 			putCnameRecord(ctx, globalDomain, name)
 		} else {
 			storage.Put(ctx, globalDomainKey, "")
@@ -836,6 +994,7 @@ func storeRecord(ctx storage.Context, recordKey []byte, name string, typ RecordT
 	recBytes := std.Serialize(rs)
 	storage.Put(ctx, recordKey, recBytes)
 	runtime.Notify("AddRecord", name, typ)
+	runtime.Notify("RecordAdded", name, typ, data)
 }
 
 // putSoaRecord stores soa domain record.
@@ -857,6 +1016,7 @@ func putSoaRecord(ctx storage.Context, name, email string, refresh, retry, expir
 	recBytes := std.Serialize(rs)
 	storage.Put(ctx, recordKey, recBytes)
 	runtime.Notify("AddRecord", name, SOA)
+	runtime.Notify("RecordAdded", name, SOA, rs.Data)
 }
 
 // putCnameRecord stores CNAME domain record.
@@ -874,6 +1034,7 @@ func putCnameRecord(ctx storage.Context, name, data string) {
 	recBytes := std.Serialize(rs)
 	storage.Put(ctx, recordKey, recBytes)
 	runtime.Notify("AddRecord", name, CNAME)
+	runtime.Notify("RecordAdded", name, CNAME, data)
 }
 
 // updateSoaSerial stores soa domain record.
@@ -1113,6 +1274,21 @@ func checkIPv6(data string) bool {
 	return true
 }
 
+// checkHash160 checks record on being a Neo N3 address.
+func checkHash160(data string) bool {
+	if len(data) != hash160RecordLength {
+		return false
+	}
+	decoded := std.Base58CheckDecode([]byte(data))
+	return len(decoded) == interop.Hash160Len+1 && decoded[0] == addressVersion
+}
+
+// hash160FromAddress returns script hash encoded in the Neo N3 address.
+func hash160FromAddress(data string) interop.Hash160 {
+	decoded := std.Base58CheckDecode([]byte(data))
+	return interop.Hash160(decoded[1:])
+}
+
 // tokenIDFromName returns token ID (domain.root) from the provided name.
 func tokenIDFromName(name string) string {
 	fragments := splitAndCheck(name)
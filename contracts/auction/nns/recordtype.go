@@ -20,3 +20,11 @@ const (
 	// AAAA represents IPv6 address record type.
 	AAAA RecordType = 28
 )
+
+// Record types which are not defined by any RFC, their values are taken from
+// the unassigned range.
+const (
+	// HASH160 represents contract script hash record type, the data is
+	// the Neo N3 address of the script hash.
+	HASH160 RecordType = 80
+)
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"contract/wrappers/nns"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/actor"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/invoker"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/spf13/viper"
+)
+
+const (
+	cfgRPCEndpoint   = "rpc_endpoint"
+	cfgNnsContract   = "nns_contract"
+	cfgDefaultWallet = "default_wallet"
+	cfgWallets       = "wallets"
+)
+
+const usage = `Usage: nnscli [-c config.yml] [-w wallet] <command> [args]
+
+Commands:
+  register [-owner address] [-email email] [-refresh n] [-retry n] [-expire n] [-ttl n] <name>
+  renew <name>
+  add-record <name> <type> <data>
+  delete-records <name> <type>
+  resolve <name> <type>
+  records <name>
+
+Record type is either its name (A, CNAME, SOA, TXT, AAAA, HASH160) or number.
+`
+
+// command is a subcommand of the CLI, only write commands get Contract and Actor.
+type command struct {
+	write bool
+	run   func(c *nns.Contract, r *nns.ContractReader, act *actor.Actor, args []string) error
+}
+
+var commands = map[string]command{
+	"register":       {write: true, run: register},
+	"renew":          {write: true, run: renew},
+	"add-record":     {write: true, run: addRecord},
+	"delete-records": {write: true, run: deleteRecords},
+	"resolve":        {run: resolve},
+	"records":        {run: records},
+}
+
+func main() {
+	if err := run(); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	ctx, _ := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+
+	fs := flag.NewFlagSet("nnscli", flag.ExitOnError)
+	fs.Usage = func() { _, _ = fmt.Fprint(os.Stderr, usage) }
+	cfgPath := fs.String("c", "nnscli/config.yml", "path to the config file")
+	walletName := fs.String("w", "", "wallet from the config to sign transactions with")
+	_ = fs.Parse(os.Args[1:])
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("no command")
+	}
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fs.Usage()
+		return fmt.Errorf("unknown command: %s", fs.Arg(0))
+	}
+
+	if err := readConfig(*cfgPath); err != nil {
+		return err
+	}
+
+	rpcCli, err := rpcclient.New(ctx, viper.GetString(cfgRPCEndpoint), rpcclient.Options{})
+	if err != nil {
+		return err
+	}
+	if err = rpcCli.Init(); err != nil {
+		return err
+	}
+
+	nnsHash, err := util.Uint160DecodeStringLE(viper.GetString(cfgNnsContract))
+	if err != nil {
+		return fmt.Errorf("invalid nns contract hash: %w", err)
+	}
+
+	if !cmd.write { // для чтения кошелек не нужен
+		return cmd.run(nil, nns.NewReader(invoker.New(rpcCli, nil), nnsHash), nil, fs.Args()[1:])
+	}
+
+	act, err := newActor(rpcCli, *walletName)
+	if err != nil {
+		return err
+	}
+	c := nns.New(act, nnsHash)
+
+	return cmd.run(c, &c.ContractReader, act, fs.Args()[1:])
+}
+
+func readConfig(path string) error {
+	viper.GetViper().SetConfigType("yml")
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return viper.GetViper().ReadConfig(f)
+}
+
+// newActor creates actor from the wallet described in the config, the first
+// account is the sender, cosigners are taken from the same wallet.
+func newActor(rpcCli *rpcclient.Client, name string) (*actor.Actor, error) {
+	if name == "" {
+		name = viper.GetString(cfgDefaultWallet)
+	}
+	key := cfgWallets + "." + name
+	if !viper.IsSet(key) {
+		return nil, fmt.Errorf("wallet %q is not configured", name)
+	}
+
+	w, err := wallet.NewWalletFromFile(viper.GetString(key + ".path"))
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := transaction.CalledByEntry
+	if s := viper.GetString(key + ".scope"); s != "" {
+		if scopes, err = transaction.ScopesFromString(s); err != nil {
+			return nil, fmt.Errorf("wallet %q: %w", name, err)
+		}
+	}
+
+	addrs := append([]string{viper.GetString(key + ".address")}, viper.GetStringSlice(key+".cosigners")...)
+	signers := make([]actor.SignerAccount, 0, len(addrs))
+	for i, addr := range addrs {
+		acc := w.GetAccount(w.GetChangeAddress()) // если адрес не указан, берем единственный аккаунт кошелька
+		if addr != "" {
+			sh, err := address.StringToUint160(addr)
+			if err != nil {
+				return nil, fmt.Errorf("wallet %q: %w", name, err)
+			}
+			acc = w.GetAccount(sh)
+		}
+		if acc == nil {
+			return nil, fmt.Errorf("wallet %q: account %s not found", name, addr)
+		}
+		if err = acc.Decrypt(viper.GetString(key+".password"), w.Scrypt); err != nil {
+			return nil, fmt.Errorf("wallet %q: %w", name, err)
+		}
+
+		signerScopes := scopes
+		if i != 0 {
+			signerScopes = transaction.Global // так же, как cosigner-ы в neo-go cli
+		}
+		signers = append(signers, actor.SignerAccount{
+			Signer:  transaction.Signer{Account: acc.ScriptHash(), Scopes: signerScopes},
+			Account: acc,
+		})
+	}
+
+	return actor.New(rpcCli, signers)
+}
+
+func register(c *nns.Contract, _ *nns.ContractReader, act *actor.Actor, args []string) error {
+	fs := flag.NewFlagSet("register", flag.ExitOnError)
+	owner := fs.String("owner", "", "domain owner address, the sender by default")
+	email := fs.String("email", "", "SOA record email")
+	refresh := fs.Int64("refresh", nns.DefaultRefresh, "SOA record refresh")
+	retry := fs.Int64("retry", nns.DefaultRetry, "SOA record retry")
+	expire := fs.Int64("expire", nns.DefaultExpire, "SOA record expire, domain lifetime in seconds")
+	ttl := fs.Int64("ttl", nns.DefaultTTL, "SOA record ttl")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("register: expected domain name")
+	}
+
+	ownerHash := act.Sender()
+	if *owner != "" {
+		var err error
+		if ownerHash, err = address.StringToUint160(*owner); err != nil {
+			return fmt.Errorf("invalid owner: %w", err)
+		}
+	}
+
+	soa := nns.SOAParams{Email: *email, Refresh: *refresh, Retry: *retry, Expire: *expire, TTL: *ttl}
+	h, vub, err := c.RegisterDomain(fs.Arg(0), ownerHash, soa)
+	return wait(act, h, vub, err)
+}
+
+func renew(c *nns.Contract, _ *nns.ContractReader, act *actor.Actor, args []string) error {
+	if len(args) != 1 {
+		return errors.New("renew: expected domain name")
+	}
+
+	h, vub, err := c.Renew(args[0])
+	return wait(act, h, vub, err)
+}
+
+func addRecord(c *nns.Contract, _ *nns.ContractReader, act *actor.Actor, args []string) error {
+	if len(args) != 3 {
+		return errors.New("add-record: expected name, type and data")
+	}
+	typ, err := nns.ParseRecordType(args[1])
+	if err != nil {
+		return err
+	}
+
+	h, vub, err := c.AddRecordType(args[0], typ, args[2])
+	return wait(act, h, vub, err)
+}
+
+func deleteRecords(c *nns.Contract, _ *nns.ContractReader, act *actor.Actor, args []string) error {
+	if len(args) != 2 {
+		return errors.New("delete-records: expected name and type")
+	}
+	typ, err := nns.ParseRecordType(args[1])
+	if err != nil {
+		return err
+	}
+
+	h, vub, err := c.DeleteRecordsType(args[0], typ)
+	return wait(act, h, vub, err)
+}
+
+func resolve(_ *nns.Contract, r *nns.ContractReader, _ *actor.Actor, args []string) error {
+	if len(args) != 2 {
+		return errors.New("resolve: expected name and type")
+	}
+	typ, err := nns.ParseRecordType(args[1])
+	if err != nil {
+		return err
+	}
+
+	res, err := r.ResolveType(args[0], typ)
+	if err != nil {
+		return err
+	}
+	for _, data := range res {
+		fmt.Println(data)
+	}
+
+	return nil
+}
+
+func records(_ *nns.Contract, r *nns.ContractReader, _ *actor.Actor, args []string) error {
+	if len(args) != 1 {
+		return errors.New("records: expected name")
+	}
+
+	res, err := r.AllRecords(args[0])
+	if err != nil {
+		return err
+	}
+	for _, rec := range res {
+		fmt.Printf("%s\t%s\t%d\t%s\n", rec.Name, rec.Type, rec.ID, rec.Data)
+	}
+
+	return nil
+}
+
+// wait waits for the transaction to be accepted and checks it has been executed successfully.
+func wait(act *actor.Actor, h util.Uint256, vub uint32, err error) error {
+	if err != nil {
+		return err
+	}
+	fmt.Println("sent tx", h.StringLE())
+
+	res, err := act.Wait(h, vub, nil)
+	if err != nil {
+		return err
+	}
+	if res.VMState != vmstate.Halt {
+		return fmt.Errorf("tx %s failed: %s", h.StringLE(), strings.TrimSpace(res.FaultException))
+	}
+
+	fmt.Println("tx accepted")
+	return nil
+}
@@ -0,0 +1,181 @@
+package nns
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// RecordType is domain name service record type, values are the same as in
+// the NameService contract.
+type RecordType byte
+
+// Record types supported by the NameService contract.
+const (
+	A       RecordType = 1
+	CNAME   RecordType = 5
+	SOA     RecordType = 6
+	TXT     RecordType = 16
+	AAAA    RecordType = 28
+	HASH160 RecordType = 80
+)
+
+// Default SOA values used on domain registration.
+const (
+	DefaultRefresh = 100
+	DefaultRetry   = 100
+	DefaultExpire  = 31536000
+	DefaultTTL     = 31536000
+)
+
+// maxRecords is the number of records expanded by AllRecords, NameService
+// doesn't allow more than 255 records of one type anyway.
+const maxRecords = 255
+
+var recordTypeNames = map[RecordType]string{
+	A:       "A",
+	CNAME:   "CNAME",
+	SOA:     "SOA",
+	TXT:     "TXT",
+	AAAA:    "AAAA",
+	HASH160: "HASH160",
+}
+
+// Record is a domain record stored in the NameService contract.
+type Record struct {
+	Name string
+	Type RecordType
+	Data string
+	ID   byte
+}
+
+// SOAParams are the parameters of SOA record set on registration.
+type SOAParams struct {
+	Email   string
+	Refresh int64
+	Retry   int64
+	Expire  int64
+	TTL     int64
+}
+
+// String returns the name of the record type.
+func (t RecordType) String() string {
+	if name, ok := recordTypeNames[t]; ok {
+		return name
+	}
+	return strconv.Itoa(int(t))
+}
+
+// BigInt returns record type as it's passed to the contract methods.
+func (t RecordType) BigInt() *big.Int {
+	return big.NewInt(int64(t))
+}
+
+// ParseRecordType parses record type either by its name (case insensitive) or its number.
+func ParseRecordType(s string) (RecordType, error) {
+	for t, name := range recordTypeNames {
+		if strings.EqualFold(s, name) {
+			return t, nil
+		}
+	}
+
+	n, err := strconv.ParseUint(s, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("unknown record type: %s", s)
+	}
+	return RecordType(n), nil
+}
+
+// DefaultSOA returns SOA parameters with default timings.
+func DefaultSOA(email string) SOAParams {
+	return SOAParams{
+		Email:   email,
+		Refresh: DefaultRefresh,
+		Retry:   DefaultRetry,
+		Expire:  DefaultExpire,
+		TTL:     DefaultTTL,
+	}
+}
+
+// AllRecords returns all records of the name, iterator is expanded right in the VM,
+// so it works with RPC servers without sessions too.
+func (c *ContractReader) AllRecords(name string) ([]Record, error) {
+	items, err := c.GetAllRecordsExpanded(name, maxRecords)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Record, 0, len(items))
+	for i, item := range items {
+		var r Record
+		if err = r.FromStackItem(item); err != nil {
+			return nil, fmt.Errorf("record %d: %w", i, err)
+		}
+		res = append(res, r)
+	}
+
+	return res, nil
+}
+
+// ResolveType resolves the name using records of the given type.
+func (c *ContractReader) ResolveType(name string, typ RecordType) ([]string, error) {
+	return c.Resolve(name, typ.BigInt())
+}
+
+// RegisterDomain registers the domain with the given SOA parameters.
+func (c *Contract) RegisterDomain(name string, owner util.Uint160, soa SOAParams) (util.Uint256, uint32, error) {
+	return c.Register(name, owner, soa.Email, big.NewInt(soa.Refresh), big.NewInt(soa.Retry),
+		big.NewInt(soa.Expire), big.NewInt(soa.TTL))
+}
+
+// AddRecordType adds a record of the given type to the name.
+func (c *Contract) AddRecordType(name string, typ RecordType, data string) (util.Uint256, uint32, error) {
+	return c.AddRecord(name, typ.BigInt(), data)
+}
+
+// DeleteRecordsType removes all records of the given type from the name.
+func (c *Contract) DeleteRecordsType(name string, typ RecordType) (util.Uint256, uint32, error) {
+	return c.DeleteRecords(name, typ.BigInt())
+}
+
+// FromStackItem retrieves fields of Record from the given RecordState item.
+func (r *Record) FromStackItem(item stackitem.Item) error {
+	if item == nil {
+		return errors.New("nil item")
+	}
+	arr, ok := item.Value().([]stackitem.Item)
+	if !ok {
+		return errors.New("not an array")
+	}
+	if len(arr) != 4 {
+		return errors.New("wrong number of structure elements")
+	}
+
+	name, err := arr[0].TryBytes()
+	if err != nil {
+		return fmt.Errorf("field Name: %w", err)
+	}
+	typ, err := arr[1].TryInteger()
+	if err != nil {
+		return fmt.Errorf("field Type: %w", err)
+	}
+	data, err := arr[2].TryBytes()
+	if err != nil {
+		return fmt.Errorf("field Data: %w", err)
+	}
+	id, err := arr[3].TryInteger()
+	if err != nil {
+		return fmt.Errorf("field ID: %w", err)
+	}
+
+	r.Name = string(name)
+	r.Type = RecordType(typ.Uint64())
+	r.Data = string(data)
+	r.ID = byte(id.Uint64())
+	return nil
+}
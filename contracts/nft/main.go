@@ -67,18 +67,31 @@ func main() {
 	// printNFTs(contractNFT, acc.ScriptHash())
 	// sellNFT(act, contractNFT, hashMarket, "my-itmo-nft")
 	// buyNFT(act, contractMarket, contractToken, hashMarket, "my-itmo-nft")
-	// createNFT(act, contractGAS, hashNFT, "my-itmo-nft")
+	// createNFT(act, contractGAS, contractNFT, hashNFT, "my-itmo-nft")
+	// renewNFT(act, contractGAS, contractNFT, hashNFT, "my-itmo-nft")
 	// transferMyTKN(act, contractMarket, acc.ScriptHash())
 
 }
 
-func createNFT(act *actor.Actor, contractGAS *nep17.Token, hashNFT util.Uint160, name string) {
-	_, err := act.WaitSuccess(contractGAS.Transfer(act.Sender(), hashNFT, big.NewInt(10_0000_0000), name)) // переводим деньги с wallet1 (т.к actor создан поверх
+func createNFT(act *actor.Actor, contractGAS *nep17.Token, c *nicenamesnft.Contract, hashNFT util.Uint160, name string) {
+	price, err := c.GetPrice(name) // узнаем цену заранее, лишнее контракт все равно вернет, но так не придется переплачивать
+	die(err)
+
+	_, err = act.WaitSuccess(contractGAS.Transfer(act.Sender(), hashNFT, price, name)) // переводим деньги с wallet1 (т.к actor создан поверх
 	// wallet1) контракту nft и как и раньше получаем новый nft с заданным именем name
 	// WaitSuccess должидается вхождения tx в блок и проверяет успешность ее применения
 	die(err)
 }
 
+func renewNFT(act *actor.Actor, contractGAS *nep17.Token, c *nicenamesnft.Contract, hashNFT util.Uint160, name string) {
+	price, err := c.GetPrice(name)
+	die(err)
+
+	_, err = act.WaitSuccess(contractGAS.Transfer(act.Sender(), hashNFT, price, "renew:"+name)) // продление
+	// владения именем, платим столько же, сколько при создании
+	die(err)
+}
+
 func printBalances(contractGAS *nep17.Token, contractToken *awesomeneotoken.Contract, owner util.Uint160) {
 	fmt.Println("Balances:")
 
@@ -93,16 +106,15 @@ func printBalances(contractGAS *nep17.Token, contractToken *awesomeneotoken.Cont
 }
 
 func sellNFT(act *actor.Actor, c *nicenamesnft.Contract, to util.Uint160, name string) {
-	for _, nft := range listNFTs(c, act.Sender()) {
-		if nft.Name == name {
-			_, err := act.WaitSuccess(c.Transfer(to, nft.ID, nil))
-			die(err)
-			return
-		}
+	owner, err := c.OwnerOfName(name) // контракт сам найдет токен по имени, перебирать свои nft не нужно
+	if err != nil || !owner.Equals(act.Sender()) {
+		fmt.Println("not found nft: ", name)
+		fmt.Println()
+		return
 	}
 
-	fmt.Println("not found nft: ", name)
-	fmt.Println()
+	_, err = act.WaitSuccess(c.TransferName(to, name, nil))
+	die(err)
 }
 
 func transferMyTKN(act *actor.Actor, c *nftmarket.Contract, to util.Uint160) {
@@ -172,11 +184,12 @@ type NFTItem struct {
 	PrevOwners int
 	Created    int
 	Bought     int
+	Expiration int
 }
 
 func (n NFTItem) String() string {
-	return fmt.Sprintf("%x\nowner: %s\nname: %s\nprevOwners: %d\ncreated block: %d\nlast bought block: %d\n",
-		n.ID, address.Uint160ToString(n.Owner), n.Name, n.PrevOwners, n.Created, n.Bought)
+	return fmt.Sprintf("%x\nowner: %s\nname: %s\nprevOwners: %d\ncreated block: %d\nlast bought block: %d\nexpiration block: %d\n",
+		n.ID, address.Uint160ToString(n.Owner), n.Name, n.PrevOwners, n.Created, n.Bought, n.Expiration)
 }
 
 func parseMap(items []stackitem.MapElement) NFTItem {
@@ -207,6 +220,9 @@ func parseMap(items []stackitem.MapElement) NFTItem {
 		case "bought":
 			res.Bought, err = strconv.Atoi(string(v))
 			die(err)
+		case "expiration":
+			res.Expiration, err = strconv.Atoi(string(v))
+			die(err)
 		}
 	}
 
@@ -30,6 +30,9 @@ const (
 	balancePrefix = "b"
 	accountPrefix = "a"
 	tokenPrefix   = "t"
+	expiryPrefix  = "e"
+	pricePrefix   = "p"
+	primaryPrefix = "r"
 
 	ownerKey       = 'o'
 	totalSupplyKey = 's'
@@ -37,8 +40,22 @@ const (
 
 const (
 	minNameLen = 3
+
+	// nameLifetime is the number of blocks a name is owned for after
+	// minting or renewal (about a year with 15 seconds blocks).
+	nameLifetime = 2_102_400
+
+	// renewPrefix marks the data of the GAS payment as a renewal request:
+	// "renew:<name>" instead of just "<name>" for minting.
+	renewPrefix = "renew:"
 )
 
+// PriceTier is the price of the names which are at least MinLen long.
+type PriceTier struct {
+	MinLen int
+	Price  int
+}
+
 type NFTItem struct {
 	ID    []byte
 	Owner interop.Hash160
@@ -53,6 +70,11 @@ type NFTItem struct {
 
 func _deploy(data interface{}, isUpdate bool) { // вызывается при деплое (и обновлении) контракта
 	if isUpdate {
+		ctx := storage.GetContext()
+		setMissingExpirations(ctx)
+		if !hasPrices(ctx) { // до появления таблицы цен они были зашиты в код
+			setDefaultPrices(ctx)
+		}
 		return
 	}
 
@@ -86,9 +108,46 @@ func _deploy(data interface{}, isUpdate bool) { // вызывается при 
 	addToken(ctx, nft.Owner, nft.ID) // обновляем список nft данного пользователя, чтобы потом
 	// удобно сразу посмотреть список nft нужного пользователя
 
+	setExpiration(ctx, nft.ID, ledger.CurrentIndex()+nameLifetime)
+
+	setDefaultPrices(ctx)
+
 	storage.Put(ctx, totalSupplyKey, 1) // по ключу хранящему общее кол-во токенов сохраняем 1, т.к создали новый токен только что
 }
 
+// Update updates the contract code, only contract owner can do it.
+func Update(script []byte, manifest []byte, data any) {
+	checkOwner(storage.GetReadOnlyContext())
+	management.UpdateWithData(script, manifest, data)
+}
+
+// GetPrice returns the amount of GAS needed to mint or renew the name.
+func GetPrice(name string) int {
+	return namePrice(storage.GetReadOnlyContext(), name)
+}
+
+// SetPrice sets the price of the names which are at least minLen long,
+// zero price removes the tier. Only contract owner can change prices.
+func SetPrice(minLen int, price int) {
+	if minLen < minNameLen {
+		panic("invalid name length")
+	}
+
+	if price < 0 {
+		panic("invalid price")
+	}
+
+	ctx := storage.GetContext()
+	checkOwner(ctx)
+
+	key := mkPriceKey(minLen)
+	if price == 0 {
+		storage.Delete(ctx, key)
+	} else {
+		storage.Put(ctx, key, std.Serialize(PriceTier{MinLen: minLen, Price: price}))
+	}
+}
+
 // Symbol returns token symbol, it's NICENAMES.
 func Symbol() string {
 	return "NICENAMES"
@@ -117,13 +176,13 @@ func BalanceOf(holder interop.Hash160) int { // сколько токенов (
 // OwnerOf returns the owner of the specified token.
 func OwnerOf(token []byte) interop.Hash160 { // узнать владельца токена
 	ctx := storage.GetReadOnlyContext()
-	return getNFT(ctx, token).Owner
+	return getActiveNFT(ctx, token).Owner
 }
 
 // Properties returns properties of the given NFT.
 func Properties(token []byte) map[string]string { // справочная инфа о nft токене
 	ctx := storage.GetReadOnlyContext()
-	nft := getNFT(ctx, token)
+	nft := getActiveNFT(ctx, token)
 
 	result := map[string]string{
 		"id":         string(nft.ID),
@@ -132,10 +191,50 @@ func Properties(token []byte) map[string]string { // справочная инф
 		"prevOwners": std.Itoa10(nft.PrevOwners),
 		"created":    std.Itoa10(nft.Created),
 		"bought":     std.Itoa10(nft.Bought),
+		"expiration": std.Itoa10(getExpiration(ctx, token)),
 	}
 	return result
 }
 
+// OwnerOfName returns the owner of the token with the given name.
+func OwnerOfName(name string) interop.Hash160 {
+	return OwnerOf(crypto.Sha256([]byte(name)))
+}
+
+// PropertiesOfName returns properties of the token with the given name.
+func PropertiesOfName(name string) map[string]string {
+	return Properties(crypto.Sha256([]byte(name)))
+}
+
+// PrimaryNameOf returns the name chosen by the account to represent it,
+// empty string is returned if there is no such name or it is expired.
+func PrimaryNameOf(holder interop.Hash160) string {
+	if len(holder) != 20 {
+		panic("bad owner address")
+	}
+	ctx := storage.GetReadOnlyContext()
+	val := storage.Get(ctx, mkPrimaryKey(holder))
+	if val == nil {
+		return ""
+	}
+	name := val.(string)
+	if isExpired(ctx, crypto.Sha256([]byte(name))) { // просроченным именем представляться нельзя
+		return ""
+	}
+	return name
+}
+
+// SetPrimaryName makes the name the primary one for its owner, so it can be
+// shown instead of the owner address.
+func SetPrimaryName(name string) {
+	ctx := storage.GetContext()
+	nft := getActiveNFT(ctx, crypto.Sha256([]byte(name)))
+	if !runtime.CheckWitness(nft.Owner) {
+		panic("not witnessed by name owner")
+	}
+	storage.Put(ctx, mkPrimaryKey(nft.Owner), name)
+}
+
 // Tokens returns an iterator that contains all the tokens minted by the contract.
 // Возвращает список всех токенов, созданных за время жизни контракта, но их может быть очень много, а на
 // стековой машине есть ограничение по кол-ву токенов, находящихся на стеке (2048). Т.е. если будем возвращать не iterator.Iterator,
@@ -200,6 +299,10 @@ func Transfer(to interop.Hash160, token []byte, data any) bool { // to - ком
 	nft := getNFT(ctx, token) // получили nft в виде структуры NFTItem
 	from := nft.Owner         // узнали, кто его хозяин
 
+	if isExpired(ctx, token) { // просроченное имя может занять кто угодно, передавать его уже нельзя
+		panic("name expired")
+	}
+
 	if !runtime.CheckWitness(from) { // проверяем, что перевести токен кому-то другому собирается сам владелец
 		// чтобы не случилось такого, что без нашего ведома распоряжаются нашими токенами
 		return false
@@ -216,12 +319,18 @@ func Transfer(to interop.Hash160, token []byte, data any) bool { // to - ком
 		removeToken(ctx, from, token) // удаляем токен из списка токенов предыдущего владельца
 		addToBalance(ctx, to, 1)
 		addToken(ctx, to, token)
+		unsetPrimaryName(ctx, from, nft.Name)
 	}
 
 	postTransfer(from, to, token, data) // различная нотификация
 	return true
 }
 
+// TransferName transfers the token with the given name, see Transfer.
+func TransferName(to interop.Hash160, name string, data any) bool {
+	return Transfer(to, crypto.Sha256([]byte(name)), data)
+}
+
 func getNFT(ctx storage.Context, token []byte) NFTItem {
 	key := mkTokenKey(token)
 	val := storage.Get(ctx, key)
@@ -235,6 +344,15 @@ func getNFT(ctx storage.Context, token []byte) NFTItem {
 	return deserializedNFT.(NFTItem)
 }
 
+// getActiveNFT returns the token like getNFT, but panics if its name is expired.
+func getActiveNFT(ctx storage.Context, token []byte) NFTItem {
+	nft := getNFT(ctx, token)
+	if isExpired(ctx, token) {
+		panic("name expired")
+	}
+	return nft
+}
+
 func nftExists(ctx storage.Context, token []byte) bool {
 	key := mkTokenKey(token)
 	return storage.Get(ctx, key) != nil
@@ -259,10 +377,13 @@ func postTransfer(from interop.Hash160, to interop.Hash160, token []byte, data a
 	}
 }
 
-// OnNEP17Payment mints tokens if at least 10 GAS is provided. You don't call
-// this method directly, instead it's called by GAS contract when you transfer
-// GAS from your address to the address of this NFT contract.
-
+// OnNEP17Payment mints the name token if at least GetPrice(name) GAS is provided,
+// the price depends on the name length and is changed by the owner with SetPrice.
+// Overpayment is refunded. You don't call this method directly, instead it's called
+// by GAS contract when you transfer GAS from your address to the address of this
+// NFT contract. Names are owned for nameLifetime blocks, to renew the name its
+// owner sends the same price with "renew:<name>" as data.
+//
 // эта функция позволяет создать nft за (газ/неотокен/самописный токен) = любой токен, в данном случае мы реализовали
 // активацию при переводе газа (в коде есть на это проверка gas.Hash) (без нее создавался только один токен при деплое контракта).
 // Но не напрямую непосредственно создавать nft, а при переводе газа на счет контракта
@@ -285,26 +406,27 @@ func OnNEP17Payment(from interop.Hash160, amount int, data any) {
 	}
 
 	name := data.(string)
-	if len(name) < 3 { // хотим min длина никнейма 3 символа
-		panic("name length at least 3 character")
+	if len(name) > len(renewPrefix) && name[:len(renewPrefix)] == renewPrefix {
+		renew(from, amount, name[len(renewPrefix):])
+		return
 	}
 
-	price := 10_0000_0000 // min цена за сколько готовы создать новый nft
-	if len(name) < 10 {   // все хотят короткие никнеймы, за такие надо платить больше
-		price += 5_0000_0000
-	}
-	if len(name) < 6 { // за еще более короткие - еще больше
-		price += 5_0000_0000
+	if len(name) < minNameLen { // хотим min длина никнейма 3 символа
+		panic("name length at least 3 character")
 	}
 
+	ctx := storage.GetContext()
+	price := namePrice(ctx, name)
 	if amount < price { // если недостаточно нам перевели за создание, то создавать nft не будем
 		panic("insufficient GAS for minting NFT")
 	}
 
-	ctx := storage.GetContext()
 	tokenID := crypto.Sha256([]byte(name))
 	if nftExists(ctx, tokenID) {
-		panic("token already exists")
+		if !isExpired(ctx, tokenID) {
+			panic("token already exists")
+		}
+		burnNFT(ctx, tokenID) // срок владения истек, имя освобождается для нового владельца
 	}
 
 	nft := NFTItem{
@@ -316,6 +438,7 @@ func OnNEP17Payment(from interop.Hash160, amount int, data any) {
 		Bought:     ledger.CurrentIndex(),
 	}
 	setNFT(ctx, tokenID, nft)
+	setExpiration(ctx, tokenID, ledger.CurrentIndex()+nameLifetime)
 	addToBalance(ctx, from, 1)
 	addToken(ctx, from, tokenID)
 
@@ -323,6 +446,143 @@ func OnNEP17Payment(from interop.Hash160, amount int, data any) {
 	storage.Put(ctx, totalSupplyKey, total)
 
 	postTransfer(nil, from, tokenID, nil)
+	refund(from, amount-price)
+}
+
+// renew extends the name ownership for one more nameLifetime, only the
+// current owner can renew the name.
+func renew(from interop.Hash160, amount int, name string) {
+	ctx := storage.GetContext()
+	tokenID := crypto.Sha256([]byte(name))
+	nft := getNFT(ctx, tokenID)
+	if !nft.Owner.Equals(from) {
+		panic("only owner can renew the name")
+	}
+
+	price := namePrice(ctx, name)
+	if amount < price {
+		panic("insufficient GAS for renewing NFT")
+	}
+
+	expiration := getExpiration(ctx, tokenID)
+	if expiration < ledger.CurrentIndex() { // если имя уже просрочено, но его никто не занял, продлеваем от текущего блока
+		expiration = ledger.CurrentIndex()
+	}
+	setExpiration(ctx, tokenID, expiration+nameLifetime)
+
+	refund(from, amount-price)
+}
+
+// namePrice returns the price of the tier with the longest MinLen which is
+// still not longer than the name.
+func namePrice(ctx storage.Context, name string) int {
+	if len(name) < minNameLen {
+		panic("name length at least 3 character")
+	}
+
+	best := PriceTier{}
+	iter := storage.Find(ctx, []byte(pricePrefix), storage.ValuesOnly|storage.DeserializeValues)
+	for iterator.Next(iter) {
+		tier := iterator.Value(iter).(PriceTier)
+		if tier.MinLen <= len(name) && tier.MinLen > best.MinLen {
+			best = tier
+		}
+	}
+
+	if best.Price == 0 {
+		panic("no price for the name")
+	}
+	return best.Price
+}
+
+// setDefaultPrices fills the price table with the values which were used
+// before prices became configurable.
+func setDefaultPrices(ctx storage.Context) {
+	storage.Put(ctx, mkPriceKey(minNameLen), std.Serialize(PriceTier{MinLen: minNameLen, Price: 20_0000_0000})) // все хотят короткие никнеймы, за такие надо платить больше
+	storage.Put(ctx, mkPriceKey(6), std.Serialize(PriceTier{MinLen: 6, Price: 15_0000_0000}))
+	storage.Put(ctx, mkPriceKey(10), std.Serialize(PriceTier{MinLen: 10, Price: 10_0000_0000}))
+}
+
+func hasPrices(ctx storage.Context) bool {
+	iter := storage.Find(ctx, []byte(pricePrefix), storage.KeysOnly)
+	return iterator.Next(iter)
+}
+
+// refund returns the GAS paid above the price back to the payer.
+func refund(to interop.Hash160, amount int) {
+	if amount <= 0 {
+		return
+	}
+	if !gas.Transfer(runtime.GetExecutingScriptHash(), to, amount, nil) {
+		panic("failed to refund GAS")
+	}
+}
+
+// checkOwner panics if the contract owner didn't sign the transaction.
+func checkOwner(ctx storage.Context) {
+	owner := storage.Get(ctx, ownerKey).(interop.Hash160)
+	if !runtime.CheckWitness(owner) {
+		panic("not witnessed by owner")
+	}
+}
+
+// burnNFT removes the token from its owner and from the contract.
+func burnNFT(ctx storage.Context, token []byte) {
+	nft := getNFT(ctx, token)
+
+	addToBalance(ctx, nft.Owner, -1)
+	removeToken(ctx, nft.Owner, token)
+	storage.Delete(ctx, mkTokenKey(token))
+	storage.Delete(ctx, mkExpiryKey(token))
+	unsetPrimaryName(ctx, nft.Owner, nft.Name)
+
+	total := storage.Get(ctx, totalSupplyKey).(int) - 1
+	storage.Put(ctx, totalSupplyKey, total)
+
+	runtime.Notify("Transfer", nft.Owner, nil, 1, token)
+}
+
+// unsetPrimaryName removes the primary name of the holder if it's the given one.
+func unsetPrimaryName(ctx storage.Context, holder interop.Hash160, name string) {
+	key := mkPrimaryKey(holder)
+	val := storage.Get(ctx, key)
+	if val != nil && val.(string) == name {
+		storage.Delete(ctx, key)
+	}
+}
+
+// getExpiration returns the block height after which the name is expired.
+func getExpiration(ctx storage.Context, token []byte) int {
+	val := storage.Get(ctx, mkExpiryKey(token))
+	if val == nil {
+		return 0
+	}
+	return val.(int)
+}
+
+func setExpiration(ctx storage.Context, token []byte, height int) {
+	storage.Put(ctx, mkExpiryKey(token), height)
+}
+
+func isExpired(ctx storage.Context, token []byte) bool {
+	return getExpiration(ctx, token) < ledger.CurrentIndex()
+}
+
+// setMissingExpirations gives a full nameLifetime to the tokens minted
+// before names started to expire.
+func setMissingExpirations(ctx storage.Context) {
+	var tokens [][]byte
+	iter := storage.Find(ctx, []byte(tokenPrefix), storage.RemovePrefix|storage.KeysOnly)
+	for iterator.Next(iter) {
+		token := iterator.Value(iter).([]byte)
+		if storage.Get(ctx, mkExpiryKey(token)) == nil {
+			tokens = append(tokens, token)
+		}
+	}
+
+	for _, token := range tokens {
+		setExpiration(ctx, token, ledger.CurrentIndex()+nameLifetime)
+	}
 }
 
 // mkAccountPrefix creates DB key-prefix for the account tokens specified
@@ -346,6 +606,27 @@ func mkTokenKey(tokenID []byte) []byte {
 	return append(res, tokenID...)
 }
 
+// mkExpiryKey creates DB key for the name expiration height by concatenating
+// expiryPrefix and token ID.
+func mkExpiryKey(tokenID []byte) []byte {
+	res := []byte(expiryPrefix)
+	return append(res, tokenID...)
+}
+
+// mkPriceKey creates DB key for the price tier by concatenating pricePrefix
+// and the minimal name length of the tier.
+func mkPriceKey(minLen int) []byte {
+	res := []byte(pricePrefix)
+	return append(res, []byte(std.Itoa10(minLen))...)
+}
+
+// mkPrimaryKey creates DB key for the primary name of the account by concatenating
+// primaryPrefix and account address.
+func mkPrimaryKey(holder interop.Hash160) []byte {
+	res := []byte(primaryPrefix)
+	return append(res, holder...)
+}
+
 // getBalanceOf returns the balance of an account using database key.
 func getBalanceOf(ctx storage.Context, balanceKey []byte) int {
 	val := storage.Get(ctx, balanceKey)
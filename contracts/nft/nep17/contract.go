@@ -3,6 +3,7 @@ package contract
 import (
 	"github.com/nspcc-dev/neo-go/pkg/interop"
 	"github.com/nspcc-dev/neo-go/pkg/interop/contract"
+	"github.com/nspcc-dev/neo-go/pkg/interop/iterator"
 	"github.com/nspcc-dev/neo-go/pkg/interop/native/management"
 	"github.com/nspcc-dev/neo-go/pkg/interop/runtime"
 	"github.com/nspcc-dev/neo-go/pkg/interop/storage"
@@ -12,15 +13,23 @@ import (
 const (
 	ownerKey       = 'o'
 	totalSupplyKey = 's'
+
+	balancePrefix   = "b"
+	allowancePrefix = "l"
 )
 
 const (
 	decimals   = 8
 	multiplier = 100000000
+
+	// migrateBatch is the number of balances migrated by the update itself, the rest
+	// is moved by Migrate calls.
+	migrateBatch = 100
 )
 
 func _deploy(data interface{}, isUpdate bool) {
 	if isUpdate {
+		migrateBalances(storage.GetContext(), migrateBatch)
 		return
 	}
 
@@ -45,8 +54,10 @@ func _deploy(data interface{}, isUpdate bool) {
 	storage.Put(ctx, ownerKey, args.Admin)
 
 	total := args.Total * multiplier
-	storage.Put(ctx, args.Admin, total)
+	addToBalance(ctx, args.Admin, total)
 	storage.Put(ctx, totalSupplyKey, total)
+
+	postTransfer(nil, args.Admin, total, nil) // без этого события кошельки и индексаторы не увидят стартовый баланс админа
 }
 
 // Symbol returns the token symbol
@@ -69,7 +80,7 @@ func BalanceOf(holder interop.Hash160) int {
 	if len(holder) != 20 {
 		panic("bad owner address")
 	}
-	return storage.Get(storage.GetReadOnlyContext(), holder).(int)
+	return getIntFromDB(storage.GetReadOnlyContext(), mkBalanceKey(holder))
 }
 
 // Transfer token from one user to another
@@ -88,23 +99,243 @@ func Transfer(from interop.Hash160, to interop.Hash160, amount int, data any) bo
 		return false
 	}
 
+	if !moveTokens(ctx, from, to, amount) {
+		return false
+	}
+
+	postTransfer(from, to, amount, data)
+	return true
+}
+
+// Approve allows spender to transfer up to amount of tokens from the transaction
+// sender's account. A new call overwrites the previous allowance.
+func Approve(spender interop.Hash160, amount int) bool {
+	if len(spender) != 20 {
+		panic("invalid spender address")
+	}
+
+	if amount < 0 {
+		panic("invalid amount")
+	}
+
+	owner := runtime.GetScriptContainer().Sender
+	if !runtime.CheckWitness(owner) {
+		return false
+	}
+
+	ctx := storage.GetContext()
+	key := mkAllowanceKey(owner, spender)
+	if amount == 0 {
+		storage.Delete(ctx, key)
+	} else {
+		storage.Put(ctx, key, amount)
+	}
+
+	runtime.Notify("Approval", owner, spender, amount)
+	return true
+}
+
+// Allowance returns the amount spender is still allowed to transfer from owner.
+func Allowance(owner interop.Hash160, spender interop.Hash160) int {
+	if len(owner) != 20 || len(spender) != 20 {
+		panic("invalid addresses")
+	}
+	return getIntFromDB(storage.GetReadOnlyContext(), mkAllowanceKey(owner, spender))
+}
+
+// TransferFrom transfers tokens from one user to another on behalf of spender,
+// the amount is deducted from the allowance given to spender by the owner.
+func TransferFrom(spender interop.Hash160, from interop.Hash160, to interop.Hash160, amount int, data any) bool {
+	ctx := storage.GetContext()
+
+	if len(spender) != 20 || len(from) != 20 || len(to) != 20 {
+		panic("invalid addresses")
+	}
+
+	if amount < 0 {
+		panic("invalid amount")
+	}
+
+	if !runtime.CheckWitness(spender) {
+		return false
+	}
+
+	key := mkAllowanceKey(from, spender)
+	allowed := getIntFromDB(ctx, key)
+	if allowed < amount { // владелец не разрешал тратить столько
+		return false
+	}
+
+	if !moveTokens(ctx, from, to, amount) {
+		return false
+	}
+
+	if allowed == amount {
+		storage.Delete(ctx, key)
+	} else {
+		storage.Put(ctx, key, allowed-amount)
+	}
+	runtime.Notify("Approval", from, spender, allowed-amount)
+
+	postTransfer(from, to, amount, data)
+	return true
+}
+
+// moveTokens updates balances of both accounts, it returns false if there
+// are not enough tokens on the sender's account.
+func moveTokens(ctx storage.Context, from interop.Hash160, to interop.Hash160, amount int) bool {
 	// наш текущий баланс токенов
-	amountFrom := getIntFromDB(ctx, from)
+	amountFrom := getIntFromDB(ctx, mkBalanceKey(from))
 	if amountFrom < amount { // пытаемся перевести больше, чем у нас есть
 		return false
 	}
-	storage.Put(ctx, from, amountFrom-amount)
+	if amount == 0 || from.Equals(to) { // балансы не меняются, но событие Transfer по стандарту все равно нужно
+		return true
+	}
 
-	// баланс токенов того, кому переводим
-	amountTo := getIntFromDB(ctx, to)
-	storage.Put(ctx, to, amountTo+amount)
+	addToBalance(ctx, from, -amount)
+	addToBalance(ctx, to, amount)
+	return true
+}
+
+// Mint creates new tokens on the specified address, only contract owner can do it.
+func Mint(to interop.Hash160, amount int) {
+	if len(to) != 20 {
+		panic("invalid 'to' address")
+	}
+
+	if amount <= 0 {
+		panic("invalid amount")
+	}
+
+	ctx := storage.GetContext()
+	checkOwner(ctx)
+
+	addToBalance(ctx, to, amount)
+	storage.Put(ctx, totalSupplyKey, storage.Get(ctx, totalSupplyKey).(int)+amount)
+
+	postTransfer(nil, to, amount, nil)
+}
+
+// Burn destroys the given amount of tokens owned by the transaction sender.
+func Burn(amount int) {
+	if amount <= 0 {
+		panic("invalid amount")
+	}
+
+	holder := runtime.GetScriptContainer().Sender // сжигаем токены того, кто отправил транзакцию
+	if !runtime.CheckWitness(holder) {
+		panic("not witnessed")
+	}
+
+	ctx := storage.GetContext()
+	if getIntFromDB(ctx, mkBalanceKey(holder)) < amount {
+		panic("insufficient balance")
+	}
 
+	addToBalance(ctx, holder, -amount)
+	storage.Put(ctx, totalSupplyKey, storage.Get(ctx, totalSupplyKey).(int)-amount)
+
+	runtime.Notify("Transfer", holder, nil, amount) // to = null означает сжигание токенов
+}
+
+// SetOwner changes the contract owner, it must be signed by the current owner.
+func SetOwner(newOwner interop.Hash160) {
+	if len(newOwner) != 20 {
+		panic("invalid owner address")
+	}
+
+	ctx := storage.GetContext()
+	checkOwner(ctx)
+	storage.Put(ctx, ownerKey, newOwner)
+}
+
+// Update updates the contract code, only contract owner can do it.
+func Update(script []byte, manifest []byte, data any) {
+	checkOwner(storage.GetReadOnlyContext())
+	management.UpdateWithData(script, manifest, data)
+}
+
+// Migrate moves up to limit balances stored without the balance prefix and returns
+// the number of moved balances, only contract owner can do it. It's called after
+// the update until it returns 0. Contracts deployed without Update method can't
+// be updated, they have to be redeployed with balances issued anew.
+func Migrate(limit int) int {
+	if limit <= 0 {
+		panic("invalid limit")
+	}
+
+	ctx := storage.GetContext()
+	checkOwner(ctx)
+	return migrateBalances(ctx, limit)
+}
+
+// checkOwner panics if the current owner didn't sign the transaction.
+func checkOwner(ctx storage.Context) {
+	owner := storage.Get(ctx, ownerKey).(interop.Hash160)
+	if !runtime.CheckWitness(owner) {
+		panic("not witnessed by owner")
+	}
+}
+
+// postTransfer emits Transfer event and calls onNEP17Payment if needed.
+// Receiver contract rejects the payment by aborting, so the whole transfer
+// fails then; contracts without onNEP17Payment can't receive tokens at all.
+func postTransfer(from interop.Hash160, to interop.Hash160, amount int, data any) {
 	runtime.Notify("Transfer", from, to, amount)
 	if management.GetContract(to) != nil { // если переводим не на обычный кошелек, а на адрес контракта, то надо вызвать
 		// у него onNEP17Payment, чтоюы он мог этот перевод обработать
+		if !management.HasMethod(to, "onNEP17Payment", 3) {
+			panic("receiver contract doesn't accept NEP-17 payments")
+		}
 		contract.Call(to, "onNEP17Payment", contract.All, from, amount, data)
 	}
-	return true
+}
+
+// migrateBalances moves balances stored by the plain account address (as it was
+// done before the balance prefix appeared) under the balancePrefix keys.
+// At most limit balances are moved, the number of moved ones is returned.
+// Zero balances are dropped.
+func migrateBalances(ctx storage.Context, limit int) int {
+	var holders []interop.Hash160
+	iter := storage.Find(ctx, []byte{}, storage.KeysOnly)
+	for len(holders) < limit && iterator.Next(iter) {
+		key := iterator.Value(iter).([]byte)
+		if len(key) == 20 { // старые ключи баланса - это просто адрес без префикса
+			holders = append(holders, key)
+		}
+	}
+
+	for _, holder := range holders {
+		amount := getIntFromDB(ctx, holder)
+		storage.Delete(ctx, holder)
+		addToBalance(ctx, holder, amount)
+	}
+	return len(holders)
+}
+
+// mkAllowanceKey creates DB key for the amount spender may transfer from owner's account.
+func mkAllowanceKey(owner interop.Hash160, spender interop.Hash160) []byte {
+	res := append([]byte(allowancePrefix), owner...)
+	return append(res, spender...)
+}
+
+// mkBalanceKey creates DB key for the account specified by concatenating balancePrefix
+// and account address.
+func mkBalanceKey(holder interop.Hash160) []byte {
+	res := []byte(balancePrefix)
+	return append(res, holder...)
+}
+
+// addToBalance adds an amount to the account balance. Amount can be negative.
+func addToBalance(ctx storage.Context, holder interop.Hash160, amount int) {
+	key := mkBalanceKey(holder)
+	balance := getIntFromDB(ctx, key) + amount
+	if balance > 0 {
+		storage.Put(ctx, key, balance)
+	} else {
+		storage.Delete(ctx, key) // нулевые балансы не храним, чтобы не платить за лишние данные
+	}
 }
 
 func getIntFromDB(ctx storage.Context, key []byte) int {
@@ -4,11 +4,28 @@
 package nicenamesnft
 
 import (
+	"errors"
+	"fmt"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/nep11"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/unwrap"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"math/big"
+	"unicode/utf8"
 )
 
+// ContractNFTItem is a contract-specific contract.NFTItem type used by its methods.
+type ContractNFTItem struct {
+	ID         []byte
+	Owner      util.Uint160
+	Name       string
+	PrevOwners *big.Int
+	Created    *big.Int
+	Bought     *big.Int
+}
+
 // Invoker is used by ContractReader to call various safe methods.
 type Invoker interface {
 	nep11.Invoker
@@ -54,6 +71,111 @@ func New(actor Actor, hash util.Uint160) *Contract {
 	return &Contract{ContractReader{nep11ndt.NonDivisibleReader, actor, hash}, nep11ndt.BaseWriter, actor, hash}
 }
 
+// GetPrice invokes `getPrice` method of contract.
+func (c *ContractReader) GetPrice(name string) (*big.Int, error) {
+	return unwrap.BigInt(c.invoker.Call(c.hash, "getPrice", name))
+}
+
+// OwnerOfName invokes `ownerOfName` method of contract.
+func (c *ContractReader) OwnerOfName(name string) (util.Uint160, error) {
+	return unwrap.Uint160(c.invoker.Call(c.hash, "ownerOfName", name))
+}
+
+// PrimaryNameOf invokes `primaryNameOf` method of contract.
+func (c *ContractReader) PrimaryNameOf(holder util.Uint160) (string, error) {
+	return unwrap.UTF8String(c.invoker.Call(c.hash, "primaryNameOf", holder))
+}
+
+// PropertiesOfName invokes `propertiesOfName` method of contract.
+func (c *ContractReader) PropertiesOfName(name string) (map[string]string, error) {
+	return func(item stackitem.Item, err error) (map[string]string, error) {
+		if err != nil {
+			return nil, err
+		}
+		return func(item stackitem.Item) (map[string]string, error) {
+			m, ok := item.Value().([]stackitem.MapElement)
+			if !ok {
+				return nil, fmt.Errorf("%s is not a map", item.Type().String())
+			}
+			res := make(map[string]string)
+			for i := range m {
+				k, err := func(item stackitem.Item) (string, error) {
+					b, err := item.TryBytes()
+					if err != nil {
+						return "", err
+					}
+					if !utf8.Valid(b) {
+						return "", errors.New("not a UTF-8 string")
+					}
+					return string(b), nil
+				}(m[i].Key)
+				if err != nil {
+					return nil, fmt.Errorf("key %d: %w", i, err)
+				}
+				v, err := func(item stackitem.Item) (string, error) {
+					b, err := item.TryBytes()
+					if err != nil {
+						return "", err
+					}
+					if !utf8.Valid(b) {
+						return "", errors.New("not a UTF-8 string")
+					}
+					return string(b), nil
+				}(m[i].Value)
+				if err != nil {
+					return nil, fmt.Errorf("value %d: %w", i, err)
+				}
+				res[k] = v
+			}
+			return res, nil
+		}(item)
+	}(unwrap.Item(c.invoker.Call(c.hash, "propertiesOfName", name)))
+}
+
+// SetPrice creates a transaction invoking `setPrice` method of the contract.
+// This transaction is signed and immediately sent to the network.
+// The values returned are its hash, ValidUntilBlock value and error if any.
+func (c *Contract) SetPrice(minLen *big.Int, price *big.Int) (util.Uint256, uint32, error) {
+	return c.actor.SendCall(c.hash, "setPrice", minLen, price)
+}
+
+// SetPriceTransaction creates a transaction invoking `setPrice` method of the contract.
+// This transaction is signed, but not sent to the network, instead it's
+// returned to the caller.
+func (c *Contract) SetPriceTransaction(minLen *big.Int, price *big.Int) (*transaction.Transaction, error) {
+	return c.actor.MakeCall(c.hash, "setPrice", minLen, price)
+}
+
+// SetPriceUnsigned creates a transaction invoking `setPrice` method of the contract.
+// This transaction is not signed, it's simply returned to the caller.
+// Any fields of it that do not affect fees can be changed (ValidUntilBlock,
+// Nonce), fee values (NetworkFee, SystemFee) can be increased as well.
+func (c *Contract) SetPriceUnsigned(minLen *big.Int, price *big.Int) (*transaction.Transaction, error) {
+	return c.actor.MakeUnsignedCall(c.hash, "setPrice", nil, minLen, price)
+}
+
+// SetPrimaryName creates a transaction invoking `setPrimaryName` method of the contract.
+// This transaction is signed and immediately sent to the network.
+// The values returned are its hash, ValidUntilBlock value and error if any.
+func (c *Contract) SetPrimaryName(name string) (util.Uint256, uint32, error) {
+	return c.actor.SendCall(c.hash, "setPrimaryName", name)
+}
+
+// SetPrimaryNameTransaction creates a transaction invoking `setPrimaryName` method of the contract.
+// This transaction is signed, but not sent to the network, instead it's
+// returned to the caller.
+func (c *Contract) SetPrimaryNameTransaction(name string) (*transaction.Transaction, error) {
+	return c.actor.MakeCall(c.hash, "setPrimaryName", name)
+}
+
+// SetPrimaryNameUnsigned creates a transaction invoking `setPrimaryName` method of the contract.
+// This transaction is not signed, it's simply returned to the caller.
+// Any fields of it that do not affect fees can be changed (ValidUntilBlock,
+// Nonce), fee values (NetworkFee, SystemFee) can be increased as well.
+func (c *Contract) SetPrimaryNameUnsigned(name string) (*transaction.Transaction, error) {
+	return c.actor.MakeUnsignedCall(c.hash, "setPrimaryName", nil, name)
+}
+
 // TokensList creates a transaction invoking `tokensList` method of the contract.
 // This transaction is signed and immediately sent to the network.
 // The values returned are its hash, ValidUntilBlock value and error if any.
@@ -97,3 +219,151 @@ func (c *Contract) TokensOfListTransaction(holder util.Uint160) (*transaction.Tr
 func (c *Contract) TokensOfListUnsigned(holder util.Uint160) (*transaction.Transaction, error) {
 	return c.actor.MakeUnsignedCall(c.hash, "tokensOfList", nil, holder)
 }
+
+func (c *Contract) scriptForTransferName(to util.Uint160, name string, data any) ([]byte, error) {
+	return smartcontract.CreateCallWithAssertScript(c.hash, "transferName", to, name, data)
+}
+
+// TransferName creates a transaction invoking `transferName` method of the contract.
+// This transaction is signed and immediately sent to the network.
+// The values returned are its hash, ValidUntilBlock value and error if any.
+func (c *Contract) TransferName(to util.Uint160, name string, data any) (util.Uint256, uint32, error) {
+	script, err := c.scriptForTransferName(to, name, data)
+	if err != nil {
+		return util.Uint256{}, 0, err
+	}
+	return c.actor.SendRun(script)
+}
+
+// TransferNameTransaction creates a transaction invoking `transferName` method of the contract.
+// This transaction is signed, but not sent to the network, instead it's
+// returned to the caller.
+func (c *Contract) TransferNameTransaction(to util.Uint160, name string, data any) (*transaction.Transaction, error) {
+	script, err := c.scriptForTransferName(to, name, data)
+	if err != nil {
+		return nil, err
+	}
+	return c.actor.MakeRun(script)
+}
+
+// TransferNameUnsigned creates a transaction invoking `transferName` method of the contract.
+// This transaction is not signed, it's simply returned to the caller.
+// Any fields of it that do not affect fees can be changed (ValidUntilBlock,
+// Nonce), fee values (NetworkFee, SystemFee) can be increased as well.
+func (c *Contract) TransferNameUnsigned(to util.Uint160, name string, data any) (*transaction.Transaction, error) {
+	script, err := c.scriptForTransferName(to, name, data)
+	if err != nil {
+		return nil, err
+	}
+	return c.actor.MakeUnsignedRun(script, nil)
+}
+
+// Update creates a transaction invoking `update` method of the contract.
+// This transaction is signed and immediately sent to the network.
+// The values returned are its hash, ValidUntilBlock value and error if any.
+func (c *Contract) Update(script []byte, manifest []byte, data any) (util.Uint256, uint32, error) {
+	return c.actor.SendCall(c.hash, "update", script, manifest, data)
+}
+
+// UpdateTransaction creates a transaction invoking `update` method of the contract.
+// This transaction is signed, but not sent to the network, instead it's
+// returned to the caller.
+func (c *Contract) UpdateTransaction(script []byte, manifest []byte, data any) (*transaction.Transaction, error) {
+	return c.actor.MakeCall(c.hash, "update", script, manifest, data)
+}
+
+// UpdateUnsigned creates a transaction invoking `update` method of the contract.
+// This transaction is not signed, it's simply returned to the caller.
+// Any fields of it that do not affect fees can be changed (ValidUntilBlock,
+// Nonce), fee values (NetworkFee, SystemFee) can be increased as well.
+func (c *Contract) UpdateUnsigned(script []byte, manifest []byte, data any) (*transaction.Transaction, error) {
+	return c.actor.MakeUnsignedCall(c.hash, "update", nil, script, manifest, data)
+}
+
+// itemToContractNFTItem converts stack item into *ContractNFTItem.
+// NULL item is returned as nil pointer without error.
+func itemToContractNFTItem(item stackitem.Item, err error) (*ContractNFTItem, error) {
+	if err != nil {
+		return nil, err
+	}
+	_, null := item.(stackitem.Null)
+	if null {
+		return nil, nil
+	}
+	var res = new(ContractNFTItem)
+	err = res.FromStackItem(item)
+	return res, err
+}
+
+// FromStackItem retrieves fields of ContractNFTItem from the given
+// [stackitem.Item] or returns an error if it's not possible to do to so.
+func (res *ContractNFTItem) FromStackItem(item stackitem.Item) error {
+	arr, ok := item.Value().([]stackitem.Item)
+	if !ok {
+		return errors.New("not an array")
+	}
+	if len(arr) != 6 {
+		return errors.New("wrong number of structure elements")
+	}
+
+	var (
+		index = -1
+		err   error
+	)
+	index++
+	res.ID, err = arr[index].TryBytes()
+	if err != nil {
+		return fmt.Errorf("field ID: %w", err)
+	}
+
+	index++
+	res.Owner, err = func(item stackitem.Item) (util.Uint160, error) {
+		b, err := item.TryBytes()
+		if err != nil {
+			return util.Uint160{}, err
+		}
+		u, err := util.Uint160DecodeBytesBE(b)
+		if err != nil {
+			return util.Uint160{}, err
+		}
+		return u, nil
+	}(arr[index])
+	if err != nil {
+		return fmt.Errorf("field Owner: %w", err)
+	}
+
+	index++
+	res.Name, err = func(item stackitem.Item) (string, error) {
+		b, err := item.TryBytes()
+		if err != nil {
+			return "", err
+		}
+		if !utf8.Valid(b) {
+			return "", errors.New("not a UTF-8 string")
+		}
+		return string(b), nil
+	}(arr[index])
+	if err != nil {
+		return fmt.Errorf("field Name: %w", err)
+	}
+
+	index++
+	res.PrevOwners, err = arr[index].TryInteger()
+	if err != nil {
+		return fmt.Errorf("field PrevOwners: %w", err)
+	}
+
+	index++
+	res.Created, err = arr[index].TryInteger()
+	if err != nil {
+		return fmt.Errorf("field Created: %w", err)
+	}
+
+	index++
+	res.Bought, err = arr[index].TryInteger()
+	if err != nil {
+		return fmt.Errorf("field Bought: %w", err)
+	}
+
+	return nil
+}
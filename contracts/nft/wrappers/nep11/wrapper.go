@@ -22,6 +22,11 @@ func Decimals() int {
 	return neogointernal.CallWithToken(Hash, "decimals", int(contract.ReadOnly)).(int)
 }
 
+// GetPrice invokes `getPrice` method of contract.
+func GetPrice(name string) int {
+	return neogointernal.CallWithToken(Hash, "getPrice", int(contract.ReadOnly), name).(int)
+}
+
 // OnNEP17Payment invokes `onNEP17Payment` method of contract.
 func OnNEP17Payment(from interop.Hash160, amount int, data any) {
 	neogointernal.CallWithTokenNoRet(Hash, "onNEP17Payment", int(contract.All), from, amount, data)
@@ -32,11 +37,36 @@ func OwnerOf(token []byte) interop.Hash160 {
 	return neogointernal.CallWithToken(Hash, "ownerOf", int(contract.ReadOnly), token).(interop.Hash160)
 }
 
+// OwnerOfName invokes `ownerOfName` method of contract.
+func OwnerOfName(name string) interop.Hash160 {
+	return neogointernal.CallWithToken(Hash, "ownerOfName", int(contract.ReadOnly), name).(interop.Hash160)
+}
+
+// PrimaryNameOf invokes `primaryNameOf` method of contract.
+func PrimaryNameOf(holder interop.Hash160) string {
+	return neogointernal.CallWithToken(Hash, "primaryNameOf", int(contract.ReadOnly), holder).(string)
+}
+
 // Properties invokes `properties` method of contract.
 func Properties(token []byte) map[string]any {
 	return neogointernal.CallWithToken(Hash, "properties", int(contract.ReadOnly), token).(map[string]any)
 }
 
+// PropertiesOfName invokes `propertiesOfName` method of contract.
+func PropertiesOfName(name string) map[string]any {
+	return neogointernal.CallWithToken(Hash, "propertiesOfName", int(contract.ReadOnly), name).(map[string]any)
+}
+
+// SetPrice invokes `setPrice` method of contract.
+func SetPrice(minLen int, price int) {
+	neogointernal.CallWithTokenNoRet(Hash, "setPrice", int(contract.All), minLen, price)
+}
+
+// SetPrimaryName invokes `setPrimaryName` method of contract.
+func SetPrimaryName(name string) {
+	neogointernal.CallWithTokenNoRet(Hash, "setPrimaryName", int(contract.All), name)
+}
+
 // Symbol invokes `symbol` method of contract.
 func Symbol() string {
 	return neogointernal.CallWithToken(Hash, "symbol", int(contract.ReadOnly)).(string)
@@ -71,3 +101,13 @@ func TotalSupply() int {
 func Transfer(to interop.Hash160, token []byte, data any) bool {
 	return neogointernal.CallWithToken(Hash, "transfer", int(contract.All), to, token, data).(bool)
 }
+
+// TransferName invokes `transferName` method of contract.
+func TransferName(to interop.Hash160, name string, data any) bool {
+	return neogointernal.CallWithToken(Hash, "transferName", int(contract.All), to, name, data).(bool)
+}
+
+// Update invokes `update` method of contract.
+func Update(script []byte, manifest []byte, data any) {
+	neogointernal.CallWithTokenNoRet(Hash, "update", int(contract.All), script, manifest, data)
+}
@@ -4,10 +4,25 @@
 package awesomeneotoken
 
 import (
+	"errors"
+	"fmt"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/nep17"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/unwrap"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"math/big"
 )
 
+// ApprovalEvent represents "Approval" event emitted by the contract.
+type ApprovalEvent struct {
+	Owner   util.Uint160
+	Spender util.Uint160
+	Amount  *big.Int
+}
+
 // Invoker is used by ContractReader to call various safe methods.
 type Invoker interface {
 	nep17.Invoker
@@ -18,6 +33,13 @@ type Actor interface {
 	Invoker
 
 	nep17.Actor
+
+	MakeCall(contract util.Uint160, method string, params ...any) (*transaction.Transaction, error)
+	MakeRun(script []byte) (*transaction.Transaction, error)
+	MakeUnsignedCall(contract util.Uint160, method string, attrs []transaction.Attribute, params ...any) (*transaction.Transaction, error)
+	MakeUnsignedRun(script []byte, attrs []transaction.Attribute) (*transaction.Transaction, error)
+	SendCall(contract util.Uint160, method string, params ...any) (util.Uint256, uint32, error)
+	SendRun(script []byte) (util.Uint256, uint32, error)
 }
 
 // ContractReader implements safe contract methods.
@@ -45,3 +67,278 @@ func New(actor Actor, hash util.Uint160) *Contract {
 	var nep17t = nep17.New(actor, hash)
 	return &Contract{ContractReader{nep17t.TokenReader, actor, hash}, nep17t.TokenWriter, actor, hash}
 }
+
+// Allowance invokes `allowance` method of contract.
+func (c *ContractReader) Allowance(owner util.Uint160, spender util.Uint160) (*big.Int, error) {
+	return unwrap.BigInt(c.invoker.Call(c.hash, "allowance", owner, spender))
+}
+
+func (c *Contract) scriptForApprove(spender util.Uint160, amount *big.Int) ([]byte, error) {
+	return smartcontract.CreateCallWithAssertScript(c.hash, "approve", spender, amount)
+}
+
+// Approve creates a transaction invoking `approve` method of the contract.
+// This transaction is signed and immediately sent to the network.
+// The values returned are its hash, ValidUntilBlock value and error if any.
+func (c *Contract) Approve(spender util.Uint160, amount *big.Int) (util.Uint256, uint32, error) {
+	script, err := c.scriptForApprove(spender, amount)
+	if err != nil {
+		return util.Uint256{}, 0, err
+	}
+	return c.actor.SendRun(script)
+}
+
+// ApproveTransaction creates a transaction invoking `approve` method of the contract.
+// This transaction is signed, but not sent to the network, instead it's
+// returned to the caller.
+func (c *Contract) ApproveTransaction(spender util.Uint160, amount *big.Int) (*transaction.Transaction, error) {
+	script, err := c.scriptForApprove(spender, amount)
+	if err != nil {
+		return nil, err
+	}
+	return c.actor.MakeRun(script)
+}
+
+// ApproveUnsigned creates a transaction invoking `approve` method of the contract.
+// This transaction is not signed, it's simply returned to the caller.
+// Any fields of it that do not affect fees can be changed (ValidUntilBlock,
+// Nonce), fee values (NetworkFee, SystemFee) can be increased as well.
+func (c *Contract) ApproveUnsigned(spender util.Uint160, amount *big.Int) (*transaction.Transaction, error) {
+	script, err := c.scriptForApprove(spender, amount)
+	if err != nil {
+		return nil, err
+	}
+	return c.actor.MakeUnsignedRun(script, nil)
+}
+
+// Burn creates a transaction invoking `burn` method of the contract.
+// This transaction is signed and immediately sent to the network.
+// The values returned are its hash, ValidUntilBlock value and error if any.
+func (c *Contract) Burn(amount *big.Int) (util.Uint256, uint32, error) {
+	return c.actor.SendCall(c.hash, "burn", amount)
+}
+
+// BurnTransaction creates a transaction invoking `burn` method of the contract.
+// This transaction is signed, but not sent to the network, instead it's
+// returned to the caller.
+func (c *Contract) BurnTransaction(amount *big.Int) (*transaction.Transaction, error) {
+	return c.actor.MakeCall(c.hash, "burn", amount)
+}
+
+// BurnUnsigned creates a transaction invoking `burn` method of the contract.
+// This transaction is not signed, it's simply returned to the caller.
+// Any fields of it that do not affect fees can be changed (ValidUntilBlock,
+// Nonce), fee values (NetworkFee, SystemFee) can be increased as well.
+func (c *Contract) BurnUnsigned(amount *big.Int) (*transaction.Transaction, error) {
+	return c.actor.MakeUnsignedCall(c.hash, "burn", nil, amount)
+}
+
+// Migrate creates a transaction invoking `migrate` method of the contract.
+// This transaction is signed and immediately sent to the network.
+// The values returned are its hash, ValidUntilBlock value and error if any.
+func (c *Contract) Migrate(limit *big.Int) (util.Uint256, uint32, error) {
+	return c.actor.SendCall(c.hash, "migrate", limit)
+}
+
+// MigrateTransaction creates a transaction invoking `migrate` method of the contract.
+// This transaction is signed, but not sent to the network, instead it's
+// returned to the caller.
+func (c *Contract) MigrateTransaction(limit *big.Int) (*transaction.Transaction, error) {
+	return c.actor.MakeCall(c.hash, "migrate", limit)
+}
+
+// MigrateUnsigned creates a transaction invoking `migrate` method of the contract.
+// This transaction is not signed, it's simply returned to the caller.
+// Any fields of it that do not affect fees can be changed (ValidUntilBlock,
+// Nonce), fee values (NetworkFee, SystemFee) can be increased as well.
+func (c *Contract) MigrateUnsigned(limit *big.Int) (*transaction.Transaction, error) {
+	return c.actor.MakeUnsignedCall(c.hash, "migrate", nil, limit)
+}
+
+// Mint creates a transaction invoking `mint` method of the contract.
+// This transaction is signed and immediately sent to the network.
+// The values returned are its hash, ValidUntilBlock value and error if any.
+func (c *Contract) Mint(to util.Uint160, amount *big.Int) (util.Uint256, uint32, error) {
+	return c.actor.SendCall(c.hash, "mint", to, amount)
+}
+
+// MintTransaction creates a transaction invoking `mint` method of the contract.
+// This transaction is signed, but not sent to the network, instead it's
+// returned to the caller.
+func (c *Contract) MintTransaction(to util.Uint160, amount *big.Int) (*transaction.Transaction, error) {
+	return c.actor.MakeCall(c.hash, "mint", to, amount)
+}
+
+// MintUnsigned creates a transaction invoking `mint` method of the contract.
+// This transaction is not signed, it's simply returned to the caller.
+// Any fields of it that do not affect fees can be changed (ValidUntilBlock,
+// Nonce), fee values (NetworkFee, SystemFee) can be increased as well.
+func (c *Contract) MintUnsigned(to util.Uint160, amount *big.Int) (*transaction.Transaction, error) {
+	return c.actor.MakeUnsignedCall(c.hash, "mint", nil, to, amount)
+}
+
+// SetOwner creates a transaction invoking `setOwner` method of the contract.
+// This transaction is signed and immediately sent to the network.
+// The values returned are its hash, ValidUntilBlock value and error if any.
+func (c *Contract) SetOwner(newOwner util.Uint160) (util.Uint256, uint32, error) {
+	return c.actor.SendCall(c.hash, "setOwner", newOwner)
+}
+
+// SetOwnerTransaction creates a transaction invoking `setOwner` method of the contract.
+// This transaction is signed, but not sent to the network, instead it's
+// returned to the caller.
+func (c *Contract) SetOwnerTransaction(newOwner util.Uint160) (*transaction.Transaction, error) {
+	return c.actor.MakeCall(c.hash, "setOwner", newOwner)
+}
+
+// SetOwnerUnsigned creates a transaction invoking `setOwner` method of the contract.
+// This transaction is not signed, it's simply returned to the caller.
+// Any fields of it that do not affect fees can be changed (ValidUntilBlock,
+// Nonce), fee values (NetworkFee, SystemFee) can be increased as well.
+func (c *Contract) SetOwnerUnsigned(newOwner util.Uint160) (*transaction.Transaction, error) {
+	return c.actor.MakeUnsignedCall(c.hash, "setOwner", nil, newOwner)
+}
+
+func (c *Contract) scriptForTransferFrom(spender util.Uint160, from util.Uint160, to util.Uint160, amount *big.Int, data any) ([]byte, error) {
+	return smartcontract.CreateCallWithAssertScript(c.hash, "transferFrom", spender, from, to, amount, data)
+}
+
+// TransferFrom creates a transaction invoking `transferFrom` method of the contract.
+// This transaction is signed and immediately sent to the network.
+// The values returned are its hash, ValidUntilBlock value and error if any.
+func (c *Contract) TransferFrom(spender util.Uint160, from util.Uint160, to util.Uint160, amount *big.Int, data any) (util.Uint256, uint32, error) {
+	script, err := c.scriptForTransferFrom(spender, from, to, amount, data)
+	if err != nil {
+		return util.Uint256{}, 0, err
+	}
+	return c.actor.SendRun(script)
+}
+
+// TransferFromTransaction creates a transaction invoking `transferFrom` method of the contract.
+// This transaction is signed, but not sent to the network, instead it's
+// returned to the caller.
+func (c *Contract) TransferFromTransaction(spender util.Uint160, from util.Uint160, to util.Uint160, amount *big.Int, data any) (*transaction.Transaction, error) {
+	script, err := c.scriptForTransferFrom(spender, from, to, amount, data)
+	if err != nil {
+		return nil, err
+	}
+	return c.actor.MakeRun(script)
+}
+
+// TransferFromUnsigned creates a transaction invoking `transferFrom` method of the contract.
+// This transaction is not signed, it's simply returned to the caller.
+// Any fields of it that do not affect fees can be changed (ValidUntilBlock,
+// Nonce), fee values (NetworkFee, SystemFee) can be increased as well.
+func (c *Contract) TransferFromUnsigned(spender util.Uint160, from util.Uint160, to util.Uint160, amount *big.Int, data any) (*transaction.Transaction, error) {
+	script, err := c.scriptForTransferFrom(spender, from, to, amount, data)
+	if err != nil {
+		return nil, err
+	}
+	return c.actor.MakeUnsignedRun(script, nil)
+}
+
+// Update creates a transaction invoking `update` method of the contract.
+// This transaction is signed and immediately sent to the network.
+// The values returned are its hash, ValidUntilBlock value and error if any.
+func (c *Contract) Update(script []byte, manifest []byte, data any) (util.Uint256, uint32, error) {
+	return c.actor.SendCall(c.hash, "update", script, manifest, data)
+}
+
+// UpdateTransaction creates a transaction invoking `update` method of the contract.
+// This transaction is signed, but not sent to the network, instead it's
+// returned to the caller.
+func (c *Contract) UpdateTransaction(script []byte, manifest []byte, data any) (*transaction.Transaction, error) {
+	return c.actor.MakeCall(c.hash, "update", script, manifest, data)
+}
+
+// UpdateUnsigned creates a transaction invoking `update` method of the contract.
+// This transaction is not signed, it's simply returned to the caller.
+// Any fields of it that do not affect fees can be changed (ValidUntilBlock,
+// Nonce), fee values (NetworkFee, SystemFee) can be increased as well.
+func (c *Contract) UpdateUnsigned(script []byte, manifest []byte, data any) (*transaction.Transaction, error) {
+	return c.actor.MakeUnsignedCall(c.hash, "update", nil, script, manifest, data)
+}
+
+// ApprovalEventsFromApplicationLog retrieves a set of all emitted events
+// with "Approval" name from the provided [result.ApplicationLog].
+func ApprovalEventsFromApplicationLog(log *result.ApplicationLog) ([]*ApprovalEvent, error) {
+	if log == nil {
+		return nil, errors.New("nil application log")
+	}
+
+	var res []*ApprovalEvent
+	for i, ex := range log.Executions {
+		for j, e := range ex.Events {
+			if e.Name != "Approval" {
+				continue
+			}
+			event := new(ApprovalEvent)
+			err := event.FromStackItem(e.Item)
+			if err != nil {
+				return nil, fmt.Errorf("failed to deserialize ApprovalEvent from stackitem (execution #%d, event #%d): %w", i, j, err)
+			}
+			res = append(res, event)
+		}
+	}
+
+	return res, nil
+}
+
+// FromStackItem converts provided [stackitem.Array] to ApprovalEvent or
+// returns an error if it's not possible to do to so.
+func (e *ApprovalEvent) FromStackItem(item *stackitem.Array) error {
+	if item == nil {
+		return errors.New("nil item")
+	}
+	arr, ok := item.Value().([]stackitem.Item)
+	if !ok {
+		return errors.New("not an array")
+	}
+	if len(arr) != 3 {
+		return errors.New("wrong number of structure elements")
+	}
+
+	var (
+		index = -1
+		err   error
+	)
+	index++
+	e.Owner, err = func(item stackitem.Item) (util.Uint160, error) {
+		b, err := item.TryBytes()
+		if err != nil {
+			return util.Uint160{}, err
+		}
+		u, err := util.Uint160DecodeBytesBE(b)
+		if err != nil {
+			return util.Uint160{}, err
+		}
+		return u, nil
+	}(arr[index])
+	if err != nil {
+		return fmt.Errorf("field Owner: %w", err)
+	}
+
+	index++
+	e.Spender, err = func(item stackitem.Item) (util.Uint160, error) {
+		b, err := item.TryBytes()
+		if err != nil {
+			return util.Uint160{}, err
+		}
+		u, err := util.Uint160DecodeBytesBE(b)
+		if err != nil {
+			return util.Uint160{}, err
+		}
+		return u, nil
+	}(arr[index])
+	if err != nil {
+		return fmt.Errorf("field Spender: %w", err)
+	}
+
+	index++
+	e.Amount, err = arr[index].TryInteger()
+	if err != nil {
+		return fmt.Errorf("field Amount: %w", err)
+	}
+
+	return nil
+}